@@ -0,0 +1,143 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// isGitHubActions reports whether the tool is running inside a GitHub Actions job
+func isGitHubActions() bool {
+	return os.Getenv("GITHUB_ACTIONS") == "true"
+}
+
+// escapeWorkflowCommand escapes a message for use in a GitHub Actions workflow command
+func escapeWorkflowCommand(message string) string {
+	message = strings.ReplaceAll(message, "%", "%25")
+	message = strings.ReplaceAll(message, "\r", "%0D")
+	return strings.ReplaceAll(message, "\n", "%0A")
+}
+
+// escapeWorkflowProperty escapes a property value such as title= in a GitHub Actions workflow command
+func escapeWorkflowProperty(value string) string {
+	value = escapeWorkflowCommand(value)
+	value = strings.ReplaceAll(value, ":", "%3A")
+	return strings.ReplaceAll(value, ",", "%2C")
+}
+
+// appendToFile appends data to a file, creating it if necessary
+func appendToFile(filename string, data []byte) error {
+	f, err := os.OpenFile(filename, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = f.Write(data)
+	return err
+}
+
+// emitGitHubAnnotations prints warning and error annotations for tickets that could not be used as evidence
+func emitGitHubAnnotations(data TransitionCheckResponse) {
+	if len(data.TicketRequested) == 0 {
+		fmt.Println("::warning title=Jira evidence::No JIRA IDs found in commit range")
+		return
+	}
+
+	for _, task := range data.Tasks {
+		if task.Type == "Error" {
+			fmt.Printf("::error title=%s::%s\n", escapeWorkflowProperty("Jira ticket "+task.Key), escapeWorkflowCommand(task.Description))
+		}
+	}
+
+	if data.Policy != nil {
+		for _, result := range data.Policy.Results {
+			if !result.Passed {
+				level := "error"
+				if result.Severity == severityWarning {
+					level = "warning"
+				}
+				fmt.Printf("::%s title=%s::%s\n", level, escapeWorkflowProperty("Policy "+result.Policy), escapeWorkflowCommand(strings.TrimSpace(result.Key+" "+result.Message)))
+			}
+		}
+	}
+
+	if data.Limits != nil && len(data.Limits.TruncatedFields) > 0 {
+		fmt.Printf("::warning title=Jira evidence size limits::Truncated %s\n", escapeWorkflowCommand(strings.Join(data.Limits.TruncatedFields, ", ")))
+	}
+
+	// A requested key can be missing when JIRA returns the issue under a different key (e.g. after a move)
+	for _, jiraID := range data.TicketRequested {
+		if !hasTask(data.Tasks, jiraID) {
+			fmt.Printf("::warning title=%s::Ticket was requested but is missing from the evidence\n", escapeWorkflowProperty("Jira ticket "+jiraID))
+		}
+	}
+}
+
+// hasTask reports whether the tasks contain an entry for the given key
+func hasTask(tasks []JiraTransitionResult, key string) bool {
+	for _, task := range tasks {
+		if task.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// writeGitHubStepSummary appends the rendered ticket summary to $GITHUB_STEP_SUMMARY
+func writeGitHubStepSummary(data TransitionCheckResponse) error {
+	summaryFile := os.Getenv("GITHUB_STEP_SUMMARY")
+	if summaryFile == "" {
+		return nil
+	}
+
+	content := generateMarkdownContent(data) + "\n"
+	if err := appendToFile(summaryFile, []byte(content)); err != nil {
+		return fmt.Errorf("error writing step summary: %v", err)
+	}
+	return nil
+}
+
+// setGitHubOutputs writes ticket_count, ticket_keys, evidence_path, predicate_type and provider_id to $GITHUB_OUTPUT
+func setGitHubOutputs(data TransitionCheckResponse, evidencePath string, tracker trackerBackend) error {
+	outputFile := os.Getenv("GITHUB_OUTPUT")
+	if outputFile == "" {
+		return nil
+	}
+
+	var keys []string
+	for _, task := range data.Tasks {
+		keys = append(keys, task.Key)
+	}
+
+	content := fmt.Sprintf("ticket_count=%d\n", len(data.Tasks))
+	content += fmt.Sprintf("ticket_keys=%s\n", strings.Join(keys, ","))
+	content += fmt.Sprintf("evidence_path=%s\n", evidencePath)
+	content += fmt.Sprintf("predicate_type=%s\n", tracker.PredicateType)
+	content += fmt.Sprintf("provider_id=%s\n", tracker.ProviderID)
+
+	if err := appendToFile(outputFile, []byte(content)); err != nil {
+		return fmt.Errorf("error writing step outputs: %v", err)
+	}
+	return nil
+}
+
+// ReportToGitHubActions publishes the step summary, annotations and step outputs when running in GitHub Actions
+func ReportToGitHubActions(data TransitionCheckResponse, evidencePath string, tracker trackerBackend) {
+	if !isGitHubActions() {
+		return
+	}
+
+	emitGitHubAnnotations(data)
+
+	// Only render a summary when there is something to show
+	if len(data.Tasks) > 0 {
+		if err := writeGitHubStepSummary(data); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+	}
+
+	if err := setGitHubOutputs(data, evidencePath, tracker); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+}
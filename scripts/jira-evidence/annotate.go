@@ -0,0 +1,200 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+)
+
+// annotationProperty is the comment property that marks comments posted by this tool
+const annotationProperty = "jira-evidence"
+
+// annotationMarker is stored in the comment property; one comment is kept per ticket and package
+type annotationMarker struct {
+	Package string `json:"package"`
+	Build   string `json:"build"`
+}
+
+// adfNode is a node of an Atlassian Document Format document
+type adfNode struct {
+	Type    string                 `json:"type"`
+	Version int                    `json:"version,omitempty"`
+	Attrs   map[string]interface{} `json:"attrs,omitempty"`
+	Content []adfNode              `json:"content,omitempty"`
+	Text    string                 `json:"text,omitempty"`
+	Marks   []adfMark              `json:"marks,omitempty"`
+}
+
+// adfMark formats an ADF text node, e.g. as code or as a link
+type adfMark struct {
+	Type  string                 `json:"type"`
+	Attrs map[string]interface{} `json:"attrs,omitempty"`
+}
+
+// entityProperty is a JIRA entity property as sent and returned by the comment API
+type entityProperty struct {
+	Key   string           `json:"key"`
+	Value annotationMarker `json:"value"`
+}
+
+// annotationComment is the subset of a JIRA v3 comment used for annotations
+type annotationComment struct {
+	ID         string           `json:"id,omitempty"`
+	Body       *adfNode         `json:"body,omitempty"`
+	Properties []entityProperty `json:"properties,omitempty"`
+}
+
+// adfText returns a text node, linked when href is not empty
+func adfText(text, href string) adfNode {
+	node := adfNode{Type: "text", Text: text}
+	if href != "" {
+		node.Marks = []adfMark{{Type: "link", Attrs: map[string]interface{}{"href": href}}}
+	}
+	return node
+}
+
+// adfCode returns a text node formatted as inline code
+func adfCode(text string) adfNode {
+	return adfNode{Type: "text", Text: text, Marks: []adfMark{{Type: "code"}}}
+}
+
+// adfListItem returns a bullet list item with a bold label followed by the given nodes
+func adfListItem(label string, nodes ...adfNode) adfNode {
+	content := []adfNode{{Type: "text", Text: label + ": ", Marks: []adfMark{{Type: "strong"}}}}
+	content = append(content, nodes...)
+	return adfNode{Type: "listItem", Content: []adfNode{{Type: "paragraph", Content: content}}}
+}
+
+// annotationBody builds the ADF comment for one ticket
+func annotationBody(build *BuildInfo, commits []string) *adfNode {
+	items := []adfNode{
+		adfListItem("Image", adfCode(build.Package())),
+		adfListItem("Build", adfText(build.BuildNumber, build.RunURL)),
+	}
+	if len(commits) > 0 {
+		var nodes []adfNode
+		for i, hash := range commits {
+			if i > 0 {
+				nodes = append(nodes, adfText(", ", ""))
+			}
+			nodes = append(nodes, adfCode(shortHash(hash)))
+		}
+		items = append(items, adfListItem("Commits", nodes...))
+	}
+	if build.EvidenceURL != "" {
+		items = append(items, adfListItem("Evidence", adfText(build.EvidenceURL, build.EvidenceURL)))
+	}
+
+	return &adfNode{
+		Type:    "doc",
+		Version: 1,
+		Content: []adfNode{
+			{Type: "paragraph", Content: []adfNode{adfText(fmt.Sprintf("Shipped in %s, build %s", build.ImageName, build.BuildNumber), "")}},
+			{Type: "bulletList", Content: items},
+		},
+	}
+}
+
+// findAnnotation returns the ID of the comment previously posted for this package, or "" if there is none
+func (jc *JiraClient) findAnnotation(ctx context.Context, key, pkg string) (string, error) {
+	for startAt := 0; ; {
+		var page struct {
+			StartAt  int                 `json:"startAt"`
+			Total    int                 `json:"total"`
+			Comments []annotationComment `json:"comments"`
+		}
+		path := fmt.Sprintf("rest/api/3/issue/%s/comment?expand=properties&maxResults=100&startAt=%d", url.PathEscape(key), startAt)
+		if err := jc.do(ctx, http.MethodGet, path, nil, &page); err != nil {
+			return "", err
+		}
+
+		for _, comment := range page.Comments {
+			for _, property := range comment.Properties {
+				if property.Key == annotationProperty && property.Value.Package == pkg {
+					return comment.ID, nil
+				}
+			}
+		}
+
+		startAt = page.StartAt + len(page.Comments)
+		if len(page.Comments) == 0 || startAt >= page.Total {
+			return "", nil
+		}
+	}
+}
+
+// Annotate posts the build comment on a ticket, or updates the comment posted earlier for the same package.
+// It returns "created" or "updated".
+func (jc *JiraClient) Annotate(key string, build *BuildInfo, commits []string) (string, error) {
+	ctx := context.Background()
+	comment := annotationComment{
+		Body:       annotationBody(build, commits),
+		Properties: []entityProperty{{Key: annotationProperty, Value: annotationMarker{Package: build.Package(), Build: build.BuildNumber}}},
+	}
+
+	id, err := jc.findAnnotation(ctx, key, build.Package())
+	if err != nil {
+		return "", err
+	}
+
+	if id == "" {
+		path := fmt.Sprintf("rest/api/3/issue/%s/comment", url.PathEscape(key))
+		return "created", jc.do(ctx, http.MethodPost, path, comment, nil)
+	}
+	path := fmt.Sprintf("rest/api/3/issue/%s/comment/%s", url.PathEscape(key), id)
+	return "updated", jc.do(ctx, http.MethodPut, path, comment, nil)
+}
+
+// runAnnotate implements the annotate subcommand: comment on every retrieved ticket of an evidence file
+func runAnnotate(args []string) int {
+	fs := flag.NewFlagSet("annotate", flag.ExitOnError)
+	evidenceFile := fs.String("evidence", "", "Evidence JSON file written by the tool (default: $OUTPUT_FILE or transformed_jira_data.json)")
+	build := registerBuildFlags(fs)
+	dryRun := registerDryRunFlags(fs)
+	fs.Parse(args)
+
+	if err := build.resolve(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return exitUsage
+	}
+
+	data, err := readEvidence(evidencePath(*evidenceFile))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return exitError
+	}
+
+	jiraClient, err := dryRun.NewJiraClient()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating JIRA client: %v\n", err)
+		return exitError
+	}
+
+	failed := 0
+	for _, task := range data.Tasks {
+		if task.Type == "Error" {
+			continue
+		}
+		action, err := jiraClient.Annotate(task.Key, build, commitsFor(data, task.Key))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %s: failed to comment: %v\n", task.Key, err)
+			failed++
+			continue
+		}
+		fmt.Printf("✅ %s: comment %s\n", task.Key, action)
+	}
+
+	if err := dryRun.finish(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return exitError
+	}
+
+	if failed > 0 {
+		fmt.Fprintf(os.Stderr, "❌ Failed to comment on %d ticket(s)\n", failed)
+		return exitError
+	}
+	return exitSuccess
+}
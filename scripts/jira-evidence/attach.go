@@ -0,0 +1,381 @@
+package main
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"flag"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// Evidence subjects supported by attach
+const (
+	subjectPackage       = "package"        // a package version; Docker images by default
+	subjectBuild         = "build"          // a build info published to Artifactory
+	subjectReleaseBundle = "release-bundle" // a release bundle v2 version
+)
+
+// dockerPackageType is the only package type whose subject path attach derives from the build information
+const dockerPackageType = "docker"
+
+// Payload types of the signed evidence
+const (
+	inTotoStatementType = "https://in-toto.io/Statement/v1"
+	inTotoPayloadType   = "application/vnd.in-toto+json"
+)
+
+// inTotoSubject identifies the artifact the evidence is about by its digest
+type inTotoSubject struct {
+	Digest map[string]string `json:"digest"`
+}
+
+// inTotoStatement is the evidence statement wrapping the predicate, as created by jf evd create
+type inTotoStatement struct {
+	Type          string          `json:"_type"`
+	Subject       []inTotoSubject `json:"subject"`
+	PredicateType string          `json:"predicateType"`
+	Predicate     json.RawMessage `json:"predicate"`
+	CreatedAt     string          `json:"createdAt"`
+	Markdown      string          `json:"markdown,omitempty"`
+}
+
+// dsseEnvelope is a signed DSSE envelope, the request body of the evidence API
+type dsseEnvelope struct {
+	Payload     string          `json:"payload"`
+	PayloadType string          `json:"payloadType"`
+	Signatures  []dsseSignature `json:"signatures"`
+}
+
+// dsseSignature is one signature of a DSSE envelope
+type dsseSignature struct {
+	KeyID string `json:"keyid"`
+	Sig   string `json:"sig"`
+}
+
+// EvidenceSubject names the artifact evidence is attached to
+type EvidenceSubject struct {
+	Kind                 string
+	RepoPath             string // explicit subject path, overrides the path derived from the coordinates
+	PackageType          string // package type of a package subject; only Docker paths are derived
+	Project              string
+	BuildName            string
+	ReleaseBundle        string
+	ReleaseBundleVersion string
+}
+
+// ArtifactoryClient talks to the Artifactory and evidence REST APIs with an access token
+type ArtifactoryClient struct {
+	restClient
+}
+
+// NewArtifactoryClient creates a client for baseURL from JF_ACCESS_TOKEN or ARTIFACTORY_ACCESS_TOKEN
+func NewArtifactoryClient(baseURL string) (*ArtifactoryClient, error) {
+	if baseURL == "" {
+		return nil, fmt.Errorf("Artifactory URL not found, set --artifactory-url or ARTIFACTORY_URL variable")
+	}
+	token := os.Getenv("JF_ACCESS_TOKEN")
+	if token == "" {
+		token = os.Getenv("ARTIFACTORY_ACCESS_TOKEN")
+	}
+	if token == "" {
+		return nil, fmt.Errorf("Artifactory access token not found, set JF_ACCESS_TOKEN or ARTIFACTORY_ACCESS_TOKEN variable")
+	}
+
+	client := &ArtifactoryClient{restClient: newRESTClient(baseURL, bearerHeaders(token))}
+	client.httpClient.Timeout = 60 * time.Second
+	return client, nil
+}
+
+// escapePath escapes each segment of a repository path
+func escapePath(repoPath string) string {
+	return (&url.URL{Path: repoPath}).EscapedPath()
+}
+
+// projectRepo returns the repository name scoped to a JFrog project, e.g. myproj-build-info
+func projectRepo(project, repo, defaultRepo string) string {
+	if project == "" {
+		return defaultRepo
+	}
+	return project + "-" + repo
+}
+
+// SubjectRepoPath returns the repository path of the file that represents the subject:
+// the image manifest of a package version, the build info JSON of a build or the release bundle manifest
+func (ac *ArtifactoryClient) SubjectRepoPath(subject EvidenceSubject, build *BuildInfo) (string, error) {
+	if subject.RepoPath != "" {
+		return strings.Trim(subject.RepoPath, "/"), nil
+	}
+
+	switch subject.Kind {
+	case subjectPackage:
+		if !strings.EqualFold(subject.PackageType, dockerPackageType) {
+			return "", fmt.Errorf("package subjects of type %s need --subject-repo-path, only Docker image paths are derived", subject.PackageType)
+		}
+		if err := build.resolve(); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%s/%s/%s/manifest.json", build.DockerRepo, build.ImageName, build.ImageVersion), nil
+
+	case subjectBuild:
+		if subject.BuildName == "" || build.BuildNumber == "" {
+			return "", fmt.Errorf("a build subject needs --build-name and --build-number")
+		}
+		// Build info files are named after the build start time in milliseconds
+		var result struct {
+			BuildInfo struct {
+				Started string `json:"started"`
+			} `json:"buildInfo"`
+		}
+		path := fmt.Sprintf("/artifactory/api/build/%s/%s", url.PathEscape(subject.BuildName), url.PathEscape(build.BuildNumber))
+		if subject.Project != "" {
+			path += "?project=" + url.QueryEscape(subject.Project)
+		}
+		if err := ac.get(path, &result); err != nil {
+			return "", fmt.Errorf("failed to get build info: %v", err)
+		}
+		started, err := parseJiraTime(result.BuildInfo.Started)
+		if err != nil {
+			return "", fmt.Errorf("failed to parse build start time: %v", err)
+		}
+		repo := projectRepo(subject.Project, "build-info", "artifactory-build-info")
+		return fmt.Sprintf("%s/%s/%s-%d.json", repo, subject.BuildName, build.BuildNumber, started.UnixMilli()), nil
+
+	case subjectReleaseBundle:
+		if subject.ReleaseBundle == "" || subject.ReleaseBundleVersion == "" {
+			return "", fmt.Errorf("a release-bundle subject needs --release-bundle and --release-bundle-version")
+		}
+		repo := projectRepo(subject.Project, "release-bundles-v2", "release-bundles-v2")
+		return fmt.Sprintf("%s/%s/%s/release-bundle.json.evd", repo, subject.ReleaseBundle, subject.ReleaseBundleVersion), nil
+	}
+
+	return "", fmt.Errorf("unknown subject %q, expected %s, %s or %s", subject.Kind, subjectPackage, subjectBuild, subjectReleaseBundle)
+}
+
+// SubjectDigest returns the sha256 checksum Artifactory stores for a repository path
+func (ac *ArtifactoryClient) SubjectDigest(repoPath string) (string, error) {
+	var info struct {
+		Checksums struct {
+			SHA256 string `json:"sha256"`
+		} `json:"checksums"`
+	}
+	if err := ac.get("/artifactory/api/storage/"+escapePath(repoPath), &info); err != nil {
+		return "", fmt.Errorf("failed to get subject checksum: %v", err)
+	}
+	if info.Checksums.SHA256 == "" {
+		return "", fmt.Errorf("%s has no sha256 checksum", repoPath)
+	}
+	return info.Checksums.SHA256, nil
+}
+
+// DeployEvidence uploads a signed envelope to the evidence API for the subject
+func (ac *ArtifactoryClient) DeployEvidence(repoPath string, envelope dsseEnvelope) (map[string]interface{}, error) {
+	var result map[string]interface{}
+	if err := ac.do(http.MethodPost, "/evidence/api/v1/subject/"+escapePath(repoPath), envelope, &result); err != nil {
+		return nil, fmt.Errorf("failed to deploy evidence: %v", err)
+	}
+	return result, nil
+}
+
+// loadSigningKey reads a PEM private key (PKCS#8, SEC 1 EC or PKCS#1 RSA) from a file path or the PEM text itself
+func loadSigningKey(value string) (crypto.Signer, error) {
+	data := []byte(value)
+	if !strings.Contains(value, "-----BEGIN") {
+		var err error
+		if data, err = os.ReadFile(value); err != nil {
+			return nil, fmt.Errorf("failed to read signing key: %v", err)
+		}
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("signing key is not PEM encoded")
+	}
+
+	if key, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		if signer, ok := key.(crypto.Signer); ok {
+			return signer, nil
+		}
+		return nil, fmt.Errorf("unsupported signing key type %T", key)
+	}
+	if key, err := x509.ParseECPrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	return nil, fmt.Errorf("unsupported signing key, expected an ECDSA, RSA or Ed25519 private key")
+}
+
+// preAuthEncoding is the DSSE pre-authentication encoding of a payload, the bytes that are signed
+func preAuthEncoding(payloadType string, payload []byte) []byte {
+	return []byte(fmt.Sprintf("DSSEv1 %d %s %d %s", len(payloadType), payloadType, len(payload), payload))
+}
+
+// signEnvelope wraps the statement in a DSSE envelope signed with key
+func signEnvelope(statement inTotoStatement, key crypto.Signer, keyID string) (dsseEnvelope, error) {
+	payload, err := json.Marshal(statement)
+	if err != nil {
+		return dsseEnvelope{}, fmt.Errorf("failed to marshal statement: %v", err)
+	}
+	message := preAuthEncoding(inTotoPayloadType, payload)
+
+	var sig []byte
+	switch k := key.(type) {
+	case ed25519.PrivateKey:
+		sig = ed25519.Sign(k, message)
+	case *ecdsa.PrivateKey:
+		digest := sha256.Sum256(message)
+		sig, err = ecdsa.SignASN1(rand.Reader, k, digest[:])
+	case *rsa.PrivateKey:
+		digest := sha256.Sum256(message)
+		sig, err = rsa.SignPKCS1v15(rand.Reader, k, crypto.SHA256, digest[:])
+	default:
+		return dsseEnvelope{}, fmt.Errorf("unsupported signing key type %T", key)
+	}
+	if err != nil {
+		return dsseEnvelope{}, fmt.Errorf("failed to sign statement: %v", err)
+	}
+
+	return dsseEnvelope{
+		Payload:     base64.StdEncoding.EncodeToString(payload),
+		PayloadType: inTotoPayloadType,
+		Signatures:  []dsseSignature{{KeyID: keyID, Sig: base64.StdEncoding.EncodeToString(sig)}},
+	}, nil
+}
+
+// runAttach implements the attach subcommand: sign the evidence file and upload it to the evidence API
+func runAttach(args []string) int {
+	fs := flag.NewFlagSet("attach", flag.ExitOnError)
+	evidenceFile := fs.String("evidence", "", "Evidence JSON file written by the tool (default: $OUTPUT_FILE or transformed_jira_data.json)")
+	predicateType := fs.String("predicate-type", "", "Predicate type (default: $PREDICATE_TYPE, then the JIRA predicate type)")
+	markdownFile := fs.String("markdown", "", "Markdown report shown with the evidence in Artifactory")
+	keyFlag := fs.String("key", "", "PEM private key, or a path to it (default: $EVIDENCE_PRIVATE_KEY, then $PRIVATE_KEY)")
+	keyAlias := fs.String("key-alias", "", "Alias of the public key registered in Artifactory (default: $EVIDENCE_KEY_ALIAS)")
+	subject := EvidenceSubject{}
+	fs.StringVar(&subject.Kind, "subject", "", "Subject: package, build or release-bundle (default: $EVIDENCE_SUBJECT, then package)")
+	fs.StringVar(&subject.RepoPath, "subject-repo-path", "", "Repository path of the subject file, overriding the derived one")
+	fs.StringVar(&subject.PackageType, "package-type", "", "Package type of a package subject (default: $PACKAGE_TYPE, then docker)")
+	fs.StringVar(&subject.Project, "project", "", "JFrog project key (default: $JF_PROJECT)")
+	fs.StringVar(&subject.BuildName, "build-name", "", "Build name for a build subject (default: $BUILD_NAME, then $JFROG_CLI_BUILD_NAME)")
+	fs.StringVar(&subject.ReleaseBundle, "release-bundle", "", "Release bundle name for a release-bundle subject (default: $RELEASE_BUNDLE_NAME)")
+	fs.StringVar(&subject.ReleaseBundleVersion, "release-bundle-version", "", "Release bundle version (default: $RELEASE_BUNDLE_VERSION)")
+	build := registerBuildFlags(fs)
+	fs.Parse(args)
+
+	envDefault := func(value *string, names ...string) {
+		for _, name := range names {
+			if *value == "" {
+				*value = os.Getenv(name)
+			}
+		}
+	}
+	envDefault(predicateType, "PREDICATE_TYPE")
+	envDefault(keyFlag, "EVIDENCE_PRIVATE_KEY", "PRIVATE_KEY")
+	envDefault(keyAlias, "EVIDENCE_KEY_ALIAS")
+	envDefault(&subject.Kind, "EVIDENCE_SUBJECT")
+	envDefault(&subject.Project, "JF_PROJECT")
+	envDefault(&subject.PackageType, "PACKAGE_TYPE")
+	envDefault(&subject.BuildName, "BUILD_NAME", "JFROG_CLI_BUILD_NAME")
+	envDefault(&subject.ReleaseBundle, "RELEASE_BUNDLE_NAME")
+	envDefault(&subject.ReleaseBundleVersion, "RELEASE_BUNDLE_VERSION")
+	if *predicateType == "" {
+		*predicateType = jiraPredicateType
+	}
+	if subject.Kind == "" {
+		subject.Kind = subjectPackage
+	}
+	if subject.PackageType == "" {
+		subject.PackageType = dockerPackageType
+	}
+	switch subject.Kind {
+	case subjectPackage, subjectBuild, subjectReleaseBundle:
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown subject %q, expected %s, %s or %s\n", subject.Kind, subjectPackage, subjectBuild, subjectReleaseBundle)
+		return exitUsage
+	}
+	if subject.Kind == subjectPackage && subject.RepoPath == "" && !strings.EqualFold(subject.PackageType, dockerPackageType) {
+		fmt.Fprintf(os.Stderr, "Error: package subjects of type %s need --subject-repo-path, only Docker image paths are derived\n", subject.PackageType)
+		return exitUsage
+	}
+	build.resolveEnv()
+
+	if *keyFlag == "" {
+		fmt.Fprintln(os.Stderr, "Error: --key is required to sign the evidence")
+		return exitUsage
+	}
+	key, err := loadSigningKey(*keyFlag)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return exitUsage
+	}
+
+	path := evidencePath(*evidenceFile)
+	predicate, err := os.ReadFile(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to read evidence file: %v\n", err)
+		return exitError
+	}
+	if !json.Valid(predicate) {
+		fmt.Fprintf(os.Stderr, "Error: evidence file %s is not valid JSON\n", path)
+		return exitError
+	}
+	var markdown []byte
+	if *markdownFile != "" {
+		if markdown, err = os.ReadFile(*markdownFile); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: failed to read markdown file: %v\n", err)
+			return exitError
+		}
+	}
+
+	client, err := NewArtifactoryClient(build.ArtifactoryURL)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return exitUsage
+	}
+	repoPath, err := client.SubjectRepoPath(subject, build)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return exitError
+	}
+	digest, err := client.SubjectDigest(repoPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return exitError
+	}
+
+	statement := inTotoStatement{
+		Type:          inTotoStatementType,
+		Subject:       []inTotoSubject{{Digest: map[string]string{"sha256": digest}}},
+		PredicateType: *predicateType,
+		Predicate:     predicate,
+		CreatedAt:     time.Now().UTC().Format(time.RFC3339),
+		Markdown:      string(markdown),
+	}
+	envelope, err := signEnvelope(statement, key, *keyAlias)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return exitError
+	}
+
+	result, err := client.DeployEvidence(repoPath, envelope)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		return exitError
+	}
+	fmt.Printf("✅ Evidence attached to %s (%s, sha256:%s)\n", repoPath, subject.Kind, digest)
+	if deployed, ok := result["path"].(string); ok {
+		fmt.Printf("Evidence path: %s\n", deployed)
+	}
+	return exitSuccess
+}
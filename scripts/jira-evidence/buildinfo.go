@@ -0,0 +1,131 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// BuildInfo identifies the build and the package the tickets shipped in, as passed by package-jira.yml
+type BuildInfo struct {
+	BuildNumber    string
+	ImageName      string
+	ImageVersion   string
+	DockerRepo     string
+	RunURL         string
+	EvidenceURL    string
+	ArtifactoryURL string
+}
+
+// registerBuildFlags adds the build and package flags shared by the write-back subcommands
+func registerBuildFlags(fs *flag.FlagSet) *BuildInfo {
+	build := &BuildInfo{}
+	fs.StringVar(&build.BuildNumber, "build-number", "", "Build number (default: $BUILD_NUMBER)")
+	fs.StringVar(&build.ImageName, "image-name", "", "Docker image name (default: $IMAGE_NAME)")
+	fs.StringVar(&build.ImageVersion, "image-version", "", "Docker image version (default: $IMAGE_VERSION, then the build number)")
+	fs.StringVar(&build.DockerRepo, "docker-repo", "", "Docker repository (default: $DOCKER_REPO)")
+	fs.StringVar(&build.RunURL, "run-url", "", "CI run URL (default: the GitHub Actions run URL)")
+	fs.StringVar(&build.EvidenceURL, "evidence-url", "", "Link to the attached evidence (default: $EVIDENCE_URL)")
+	fs.StringVar(&build.ArtifactoryURL, "artifactory-url", "", "Artifactory base URL (default: $ARTIFACTORY_URL, then $JF_URL)")
+	return build
+}
+
+// resolve fills unset values from the environment and checks the required ones
+func (b *BuildInfo) resolve() error {
+	b.resolveEnv()
+
+	var missing []string
+	if b.BuildNumber == "" {
+		missing = append(missing, "--build-number")
+	}
+	if b.ImageName == "" {
+		missing = append(missing, "--image-name")
+	}
+	if b.DockerRepo == "" {
+		missing = append(missing, "--docker-repo")
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("missing build information: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// resolveEnv fills unset values from the environment without checking them
+func (b *BuildInfo) resolveEnv() {
+	envDefault := func(value *string, name string) {
+		if *value == "" {
+			*value = os.Getenv(name)
+		}
+	}
+	envDefault(&b.BuildNumber, "BUILD_NUMBER")
+	envDefault(&b.ImageName, "IMAGE_NAME")
+	envDefault(&b.ImageVersion, "IMAGE_VERSION")
+	envDefault(&b.DockerRepo, "DOCKER_REPO")
+	envDefault(&b.EvidenceURL, "EVIDENCE_URL")
+	envDefault(&b.ArtifactoryURL, "ARTIFACTORY_URL")
+	envDefault(&b.ArtifactoryURL, "JF_URL")
+	b.ArtifactoryURL = strings.TrimRight(b.ArtifactoryURL, "/")
+	if b.ImageVersion == "" {
+		b.ImageVersion = b.BuildNumber
+	}
+	if b.RunURL == "" && os.Getenv("GITHUB_RUN_ID") != "" {
+		b.RunURL = fmt.Sprintf("%s/%s/actions/runs/%s",
+			os.Getenv("GITHUB_SERVER_URL"), os.Getenv("GITHUB_REPOSITORY"), os.Getenv("GITHUB_RUN_ID"))
+	}
+}
+
+// Package returns the package coordinates as repo/name:version
+func (b *BuildInfo) Package() string {
+	return fmt.Sprintf("%s/%s:%s", b.DockerRepo, b.ImageName, b.ImageVersion)
+}
+
+// PackageURL returns the package version in the Artifactory UI, or "" when no Artifactory URL is set
+func (b *BuildInfo) PackageURL() string {
+	if b.ArtifactoryURL == "" {
+		return ""
+	}
+	// Image names may contain slashes, which are path segments in the repository tree
+	path := (&url.URL{Path: b.DockerRepo + "/" + b.ImageName + "/" + b.ImageVersion}).EscapedPath()
+	return b.ArtifactoryURL + "/ui/repos/tree/General/" + path
+}
+
+// readEvidence loads a JSON evidence file written by the tool
+func readEvidence(path string) (TransitionCheckResponse, error) {
+	var data TransitionCheckResponse
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return data, fmt.Errorf("failed to read evidence file: %v", err)
+	}
+	if err := json.Unmarshal(content, &data); err != nil {
+		return data, fmt.Errorf("failed to parse evidence file %s: %v", path, err)
+	}
+	return data, nil
+}
+
+// evidencePath returns the evidence file to read, defaulting like the main output file
+func evidencePath(path string) string {
+	if path == "" {
+		path = os.Getenv("OUTPUT_FILE")
+	}
+	if path == "" {
+		path = "transformed_jira_data.json"
+	}
+	return path
+}
+
+// commitsFor returns the hashes of the evidence commits that reference key
+func commitsFor(data TransitionCheckResponse, key string) []string {
+	var hashes []string
+	for _, commit := range data.Commits {
+		for _, jiraID := range commit.JiraIDs {
+			if jiraID == key {
+				hashes = append(hashes, commit.Hash)
+				break
+			}
+		}
+	}
+	return hashes
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Config is the optional JSON configuration file for settings that do not fit in flags or environment variables
+type Config struct {
+	Rules             []RuleConfig                   `json:"rules"`
+	RequiredWorkflows map[string]WorkflowRequirement `json:"required_workflows"`
+	Security          SecurityConfig                 `json:"security"`
+	Redaction         RedactionConfig                `json:"redaction"`
+	Limits            LimitsConfig                   `json:"limits"`
+	Trackers          map[string]string              `json:"trackers"`
+}
+
+// loadConfig reads the configuration file; an empty path yields an empty configuration
+func loadConfig(path string) (Config, error) {
+	var config Config
+	if path == "" {
+		return config, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return config, fmt.Errorf("failed to read config file: %v", err)
+	}
+
+	if err := json.Unmarshal(data, &config); err != nil {
+		return config, fmt.Errorf("failed to parse config file %s: %v", path, err)
+	}
+
+	if err := validateWorkflowRequirements(config.RequiredWorkflows); err != nil {
+		return config, fmt.Errorf("invalid config file %s: %v", path, err)
+	}
+
+	if _, err := config.Security.compileKeyPatterns(); err != nil {
+		return config, fmt.Errorf("invalid config file %s: %v", path, err)
+	}
+
+	if err := config.Redaction.validate(); err != nil {
+		return config, fmt.Errorf("invalid config file %s: %v", path, err)
+	}
+
+	if err := config.Limits.validate(); err != nil {
+		return config, fmt.Errorf("invalid config file %s: %v", path, err)
+	}
+
+	if err := validateTrackerRoutes(config.Trackers); err != nil {
+		return config, fmt.Errorf("invalid config file %s: %v", path, err)
+	}
+
+	return config, nil
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+)
+
+// RecordedRequest is a mutating JIRA request that a dry run did not send
+type RecordedRequest struct {
+	Method string          `json:"method"`
+	Path   string          `json:"path"`
+	Body   json.RawMessage `json:"body,omitempty"`
+}
+
+// requestRecorder is an http.RoundTripper that passes reads through and records every other request.
+// Recorded requests get a 200 response echoing their body, so callers decode what they sent;
+// a created version also gets a synthetic id so later requests can refer to it.
+type requestRecorder struct {
+	next     http.RoundTripper
+	mu       sync.Mutex
+	requests []RecordedRequest
+}
+
+// RoundTrip implements http.RoundTripper
+func (r *requestRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method == http.MethodGet || req.Method == http.MethodHead || req.Method == http.MethodOptions {
+		return r.next.RoundTrip(req)
+	}
+
+	var body []byte
+	if req.Body != nil {
+		var err error
+		if body, err = io.ReadAll(req.Body); err != nil {
+			return nil, fmt.Errorf("dry run: failed to read request body: %v", err)
+		}
+		req.Body.Close()
+	}
+	body = bytes.TrimSpace(body)
+
+	recorded := RecordedRequest{Method: req.Method, Path: req.URL.RequestURI()}
+	if json.Valid(body) {
+		recorded.Body = body
+	}
+	r.mu.Lock()
+	r.requests = append(r.requests, recorded)
+	count := len(r.requests)
+	r.mu.Unlock()
+	fmt.Fprintf(os.Stderr, "🔍 Dry run, not sent: %s %s %s\n", recorded.Method, recorded.Path, body)
+
+	if len(body) == 0 {
+		body = []byte("{}")
+	}
+	if req.Method == http.MethodPost && strings.HasSuffix(req.URL.Path, "/rest/api/3/version") {
+		body = withSyntheticID(body, fmt.Sprintf("dry-run-%d", count))
+	}
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// withSyntheticID sets the id of a JSON object, leaving anything else unchanged
+func withSyntheticID(body []byte, id string) []byte {
+	var object map[string]interface{}
+	if err := json.Unmarshal(body, &object); err != nil || object == nil {
+		return body
+	}
+	object["id"] = id
+	if withID, err := json.Marshal(object); err == nil {
+		return withID
+	}
+	return body
+}
+
+// DryRun holds the --dry-run options shared by the write-back subcommands
+type DryRun struct {
+	Enabled  bool
+	Output   string
+	recorder *requestRecorder
+}
+
+// registerDryRunFlags adds the dry-run flags to a subcommand
+func registerDryRunFlags(fs *flag.FlagSet) *DryRun {
+	dryRun := &DryRun{}
+	fs.BoolVar(&dryRun.Enabled, "dry-run", false, "Record the JIRA write requests instead of sending them (default: $JIRA_DRY_RUN)")
+	fs.StringVar(&dryRun.Output, "dry-run-output", "", "File for the recorded requests (default: $JIRA_DRY_RUN_OUTPUT or dry_run_requests.json)")
+	return dryRun
+}
+
+// dryRunSubcommands are the subcommands that accept the dry-run flags
+var dryRunSubcommands = map[string]bool{"annotate": true, "fix-version": true, "transition": true, "remote-link": true}
+
+// hoistDryRunFlags moves dry-run flags given before a subcommand name (--dry-run annotate) behind it,
+// where the subcommand parses them. Other arguments are returned unchanged.
+func hoistDryRunFlags(args []string) []string {
+	var dryRunFlags []string
+	for i := 0; i < len(args); i++ {
+		name, _, hasValue := strings.Cut(strings.TrimLeft(args[i], "-"), "=")
+		switch {
+		case !strings.HasPrefix(args[i], "-"):
+			if len(dryRunFlags) == 0 || !dryRunSubcommands[args[i]] {
+				return args
+			}
+			hoisted := append([]string{args[i]}, dryRunFlags...)
+			return append(hoisted, args[i+1:]...)
+		case name == "dry-run":
+			dryRunFlags = append(dryRunFlags, args[i])
+		case name == "dry-run-output" && hasValue:
+			dryRunFlags = append(dryRunFlags, args[i])
+		case name == "dry-run-output" && i+1 < len(args):
+			dryRunFlags = append(dryRunFlags, args[i], args[i+1])
+			i++
+		default:
+			return args
+		}
+	}
+	return args
+}
+
+// NewJiraClient creates the JIRA client, recording instead of sending write requests in a dry run
+func (d *DryRun) NewJiraClient() (*JiraClient, error) {
+	if !d.Enabled && os.Getenv("JIRA_DRY_RUN") == "true" {
+		d.Enabled = true
+	}
+	if !d.Enabled {
+		return NewJiraClient()
+	}
+	fmt.Fprintln(os.Stderr, "🔍 Dry run: JIRA reads are executed, writes are only recorded")
+	d.recorder = &requestRecorder{next: http.DefaultTransport}
+	return newJiraClientWithTransport(d.recorder)
+}
+
+// finish writes the recorded requests; it does nothing unless the client was created for a dry run
+func (d *DryRun) finish() error {
+	if d.recorder == nil {
+		return nil
+	}
+	if d.Output == "" {
+		d.Output = os.Getenv("JIRA_DRY_RUN_OUTPUT")
+		if d.Output == "" {
+			d.Output = "dry_run_requests.json"
+		}
+	}
+
+	requests := d.recorder.requests
+	if requests == nil {
+		requests = []RecordedRequest{}
+	}
+	jsonBytes, err := json.MarshalIndent(requests, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal recorded requests: %v", err)
+	}
+	if err := writeToFile(d.Output, jsonBytes); err != nil {
+		return fmt.Errorf("failed to write recorded requests: %v", err)
+	}
+	fmt.Printf("🔍 Dry run: %d write request(s) recorded in %s\n", len(requests), d.Output)
+	return nil
+}
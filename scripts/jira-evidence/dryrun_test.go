@@ -0,0 +1,30 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHoistDryRunFlags(t *testing.T) {
+	tests := []struct {
+		args []string
+		want []string
+	}{
+		{[]string{"--dry-run", "annotate", "--evidence", "ev.json"}, []string{"annotate", "--dry-run", "--evidence", "ev.json"}},
+		{[]string{"-dry-run=true", "--dry-run-output", "out.json", "fix-version"}, []string{"fix-version", "-dry-run=true", "--dry-run-output", "out.json"}},
+		{[]string{"--dry-run-output=out.json", "--dry-run", "transition", "--to", "Done"}, []string{"transition", "--dry-run-output=out.json", "--dry-run", "--to", "Done"}},
+		{[]string{"annotate", "--dry-run"}, []string{"annotate", "--dry-run"}},
+		// attach does not write to JIRA, and the primary mode has no dry run
+		{[]string{"--dry-run", "attach"}, []string{"--dry-run", "attach"}},
+		{[]string{"--dry-run", "abc123def456"}, []string{"--dry-run", "abc123def456"}},
+		{[]string{"-o", "out.json", "annotate"}, []string{"-o", "out.json", "annotate"}},
+		{[]string{"--dry-run"}, []string{"--dry-run"}},
+		{nil, nil},
+	}
+	for _, tt := range tests {
+		got := hoistDryRunFlags(tt.args)
+		if strings.Join(got, " ") != strings.Join(tt.want, " ") || len(got) != len(tt.want) {
+			t.Errorf("hoistDryRunFlags(%q) = %q, want %q", tt.args, got, tt.want)
+		}
+	}
+}
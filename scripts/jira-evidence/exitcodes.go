@@ -0,0 +1,101 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Exit codes returned by the tool, documented in the README
+const (
+	exitSuccess         = 0 // evidence produced, or a non-fatal condition occurred
+	exitError           = 1 // internal error, e.g. JIRA client creation or writing the output
+	exitUsage           = 2 // invalid flags, arguments or configuration
+	exitGitError        = 3 // not a git repository, or HEAD/start commit missing
+	exitNoTickets       = 4 // no tickets found in the commit range
+	exitPartialFetch    = 5 // some tickets could not be retrieved
+	exitTotalFetch      = 6 // no ticket could be retrieved
+	exitPolicyViolation = 7 // an error-severity policy failed
+)
+
+// Conditions accepted by --fail-on
+const (
+	failOnGit          = "git"
+	failOnNoTickets    = "no-tickets"
+	failOnPartialFetch = "partial-fetch"
+	failOnTotalFetch   = "total-fetch"
+	failOnPolicy       = "policy"
+)
+
+// failConditions lists the accepted --fail-on conditions
+var failConditions = []string{failOnGit, failOnNoTickets, failOnPartialFetch, failOnTotalFetch, failOnPolicy}
+
+// defaultFailOn keeps the historical behavior for git and empty ranges while failing on fetch and policy errors
+const defaultFailOn = "total-fetch,policy"
+
+// FailOn is the set of conditions that make the run exit non-zero
+type FailOn map[string]bool
+
+// parseFailOn parses a comma-separated list of fatal conditions; "none" disables all of them
+func parseFailOn(value string) (FailOn, error) {
+	failOn := make(FailOn)
+	for _, condition := range parseColumns(value) {
+		condition = strings.ToLower(condition)
+		if condition == "none" {
+			continue
+		}
+		if !containsFold(failConditions, condition) {
+			return nil, fmt.Errorf("unknown --fail-on condition %q, expected one of: %s, none", condition, strings.Join(failConditions, ", "))
+		}
+		failOn[condition] = true
+	}
+	return failOn, nil
+}
+
+// exitFor exits with code when condition is fatal, otherwise the run stops successfully as before
+func exitFor(failOn FailOn, condition string, code int) {
+	os.Exit(conditionExitCode(failOn, condition, code))
+}
+
+// conditionExitCode returns code when condition is fatal and exitSuccess otherwise
+func conditionExitCode(failOn FailOn, condition string, code int) int {
+	if failOn[condition] {
+		return code
+	}
+	return exitSuccess
+}
+
+// failedTickets counts the requested tickets that could not be retrieved
+func failedTickets(data TransitionCheckResponse) int {
+	failed := 0
+	for _, task := range data.Tasks {
+		if task.Type == "Error" {
+			failed++
+		}
+	}
+	return failed
+}
+
+// resultExitCode returns the exit code for a completed run: fetch failures take precedence over policy failures.
+// maxErrors fails the run when more tickets than that could not be retrieved; a negative value disables it.
+// trackerName is the display name of the tracker the tickets were fetched from.
+func resultExitCode(data TransitionCheckResponse, failOn FailOn, maxErrors int, trackerName string) (int, string) {
+	failed := failedTickets(data)
+	overThreshold := maxErrors >= 0 && failed > maxErrors
+	exceeded := failed > 0 && (failOn[failOnPartialFetch] || overThreshold)
+
+	if failed > 0 && failed == len(data.Tasks) && (failOn[failOnTotalFetch] || exceeded) {
+		return exitTotalFetch, fmt.Sprintf("none of the %d ticket(s) could be retrieved from %s", failed, trackerName)
+	}
+	if exceeded {
+		message := fmt.Sprintf("%d of %d ticket(s) could not be retrieved from %s", failed, len(data.Tasks), trackerName)
+		if overThreshold {
+			message += fmt.Sprintf(", more than the %d allowed by --max-errors", maxErrors)
+		}
+		return exitPartialFetch, message
+	}
+	if data.Policy != nil && !data.Policy.Passed && failOn[failOnPolicy] {
+		return exitPolicyViolation, "Policy check failed, see policy results in the evidence"
+	}
+	return exitSuccess, ""
+}
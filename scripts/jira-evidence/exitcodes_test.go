@@ -0,0 +1,131 @@
+package main
+
+import "testing"
+
+func TestParseFailOn(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    []string
+		wantErr bool
+	}{
+		{"", nil, false},
+		{"none", nil, false},
+		{defaultFailOn, []string{failOnTotalFetch, failOnPolicy}, false},
+		{"git, No-Tickets", []string{failOnGit, failOnNoTickets}, false},
+		{"git,no-tickets,partial-fetch,total-fetch,policy", failConditions, false},
+		{"policy,bogus", nil, true},
+	}
+	for _, tt := range tests {
+		failOn, err := parseFailOn(tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseFailOn(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			continue
+		}
+		if tt.wantErr {
+			continue
+		}
+		if len(failOn) != len(tt.want) {
+			t.Errorf("parseFailOn(%q) = %v, want %v", tt.value, failOn, tt.want)
+		}
+		for _, condition := range tt.want {
+			if !failOn[condition] {
+				t.Errorf("parseFailOn(%q) is missing %s", tt.value, condition)
+			}
+		}
+	}
+}
+
+func TestConditionExitCode(t *testing.T) {
+	tests := []struct {
+		failOn    string
+		condition string
+		code      int
+		want      int
+	}{
+		{defaultFailOn, failOnGit, exitGitError, exitSuccess},
+		{defaultFailOn, failOnNoTickets, exitNoTickets, exitSuccess},
+		{"git", failOnGit, exitGitError, exitGitError},
+		{"git", failOnNoTickets, exitNoTickets, exitSuccess},
+		{"no-tickets", failOnNoTickets, exitNoTickets, exitNoTickets},
+		{"none", failOnGit, exitGitError, exitSuccess},
+	}
+	for _, tt := range tests {
+		failOn, err := parseFailOn(tt.failOn)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := conditionExitCode(failOn, tt.condition, tt.code); got != tt.want {
+			t.Errorf("--fail-on %q, %s: exit code %d, want %d", tt.failOn, tt.condition, got, tt.want)
+		}
+	}
+}
+
+// evidenceWith returns evidence with ok retrieved tickets, failed error tasks and the given policy outcome
+func evidenceWith(ok, failed int, policyPassed bool) TransitionCheckResponse {
+	var data TransitionCheckResponse
+	for i := 0; i < ok; i++ {
+		data.Tasks = append(data.Tasks, storyTicket("EV-1", "Done"))
+	}
+	for i := 0; i < failed; i++ {
+		data.Tasks = append(data.Tasks, JiraTransitionResult{Key: "EV-2", Type: "Error"})
+	}
+	data.Policy = &PolicyReport{Passed: policyPassed}
+	return data
+}
+
+func TestResultExitCode(t *testing.T) {
+	tests := []struct {
+		name      string
+		failOn    string
+		maxErrors int
+		data      TransitionCheckResponse
+		want      int
+	}{
+		{"default, all retrieved", defaultFailOn, -1, evidenceWith(3, 0, true), exitSuccess},
+		{"default, partial fetch", defaultFailOn, -1, evidenceWith(2, 1, true), exitSuccess},
+		{"default, total fetch", defaultFailOn, -1, evidenceWith(0, 3, true), exitTotalFetch},
+		{"default, policy failed", defaultFailOn, -1, evidenceWith(3, 0, false), exitPolicyViolation},
+		{"default, fetch takes precedence over policy", defaultFailOn, -1, evidenceWith(0, 2, false), exitTotalFetch},
+		{"none, total fetch", "none", -1, evidenceWith(0, 3, false), exitSuccess},
+		{"partial-fetch, partial fetch", "partial-fetch", -1, evidenceWith(2, 1, true), exitPartialFetch},
+		{"partial-fetch, total fetch", "partial-fetch", -1, evidenceWith(0, 2, true), exitTotalFetch},
+		{"partial-fetch, policy failed", "partial-fetch", -1, evidenceWith(3, 0, false), exitSuccess},
+		{"total-fetch, partial fetch", "total-fetch", -1, evidenceWith(2, 1, true), exitSuccess},
+		{"policy, total fetch", "policy", -1, evidenceWith(0, 2, true), exitSuccess},
+		{"policy, policy failed", "policy", -1, evidenceWith(3, 0, false), exitPolicyViolation},
+		{"partial-fetch and policy, both", "partial-fetch,policy", -1, evidenceWith(2, 1, false), exitPartialFetch},
+		{"max-errors 0, partial fetch", "none", 0, evidenceWith(2, 1, true), exitPartialFetch},
+		{"max-errors 1, one failed", "none", 1, evidenceWith(2, 1, true), exitSuccess},
+		{"max-errors 1, two failed", "none", 1, evidenceWith(2, 2, true), exitPartialFetch},
+		{"max-errors 0, total fetch", "none", 0, evidenceWith(0, 2, true), exitTotalFetch},
+		{"no policy report", defaultFailOn, -1, TransitionCheckResponse{Tasks: []JiraTransitionResult{{Key: "EV-1"}}}, exitSuccess},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			failOn, err := parseFailOn(tt.failOn)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, message := resultExitCode(tt.data, failOn, tt.maxErrors, "JIRA")
+			if got != tt.want {
+				t.Errorf("resultExitCode() = %d (%s), want %d", got, message, tt.want)
+			}
+			if (got == exitSuccess) != (message == "") {
+				t.Errorf("resultExitCode() message %q does not match exit code %d", message, got)
+			}
+		})
+	}
+}
+
+func TestResultExitCodeTrackerName(t *testing.T) {
+	failOn, err := parseFailOn("partial-fetch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, message := resultExitCode(evidenceWith(0, 2, true), failOn, -1, "GitLab"); message != "none of the 2 ticket(s) could be retrieved from GitLab" {
+		t.Errorf("total fetch message = %q", message)
+	}
+	if _, message := resultExitCode(evidenceWith(1, 1, true), failOn, -1, "YouTrack"); message != "1 of 2 ticket(s) could not be retrieved from YouTrack" {
+		t.Errorf("partial fetch message = %q", message)
+	}
+}
@@ -0,0 +1,265 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// ExportOptions controls how the response is serialized
+type ExportOptions struct {
+	Format  string   // json, csv or jsonl
+	Rows    string   // tickets or transitions (csv and jsonl only)
+	Columns []string // column names; defaults depend on Rows
+}
+
+// defaultTicketColumns are the columns exported per ticket when none are configured
+var defaultTicketColumns = []string{
+	"key", "status", "type", "project", "priority", "assignee", "reporter", "created", "updated", "description", "transition_count",
+}
+
+// defaultTransitionColumns are the columns exported per transition when none are configured
+var defaultTransitionColumns = []string{
+	"key", "from_status", "to_status", "author", "author_user_name", "transition_time", "transition_time_utc",
+}
+
+// validateExportOptions checks the format, row type and column names
+func validateExportOptions(opts ExportOptions) error {
+	switch opts.Format {
+	case "json", "csv", "jsonl":
+	default:
+		return fmt.Errorf("unsupported format %q, expected json, csv or jsonl", opts.Format)
+	}
+	switch opts.Rows {
+	case "tickets", "transitions":
+	default:
+		return fmt.Errorf("unsupported rows %q, expected tickets or transitions", opts.Rows)
+	}
+	// Check the columns now rather than after every ticket has been fetched
+	for _, column := range opts.Columns {
+		_, ok := ticketValue(JiraTransitionResult{}, column)
+		if !ok && opts.Rows == "transitions" {
+			_, ok = transitionValue(Transition{}, column)
+		}
+		if !ok {
+			return fmt.Errorf("unknown %s column %q", strings.TrimSuffix(opts.Rows, "s"), column)
+		}
+	}
+	return nil
+}
+
+// parseColumns splits a comma-separated column list
+func parseColumns(value string) []string {
+	var columns []string
+	for _, column := range strings.Split(value, ",") {
+		if column = strings.TrimSpace(column); column != "" {
+			columns = append(columns, column)
+		}
+	}
+	return columns
+}
+
+// customFieldColumns returns the columns that refer to JIRA custom fields
+func customFieldColumns(columns []string) []string {
+	var fields []string
+	for _, column := range columns {
+		if strings.HasPrefix(column, "customfield_") {
+			fields = append(fields, column)
+		}
+	}
+	return fields
+}
+
+// exportFileName derives the default output file name for a format
+func exportFileName(outputFile, format string) string {
+	if format == "json" {
+		return outputFile
+	}
+	return strings.TrimSuffix(outputFile, filepath.Ext(outputFile)) + "." + format
+}
+
+// validateOutputFile rejects an output file whose extension names a different export format,
+// such as CSV written to evidence.json
+func validateOutputFile(outputFile, format string) error {
+	ext := strings.TrimPrefix(strings.ToLower(filepath.Ext(outputFile)), ".")
+	switch ext {
+	case "json", "csv", "jsonl":
+		if ext != format {
+			return fmt.Errorf("output file %s does not match format %s, use a .%s file", outputFile, format, format)
+		}
+	}
+	return nil
+}
+
+// ticketValue returns the value of a ticket column
+func ticketValue(task JiraTransitionResult, column string) (interface{}, bool) {
+	switch column {
+	case "key":
+		return task.Key, true
+	case "summary":
+		return task.Summary, true
+	case "status":
+		return task.Status, true
+	case "description":
+		return task.Description, true
+	case "type":
+		return task.Type, true
+	case "project":
+		return task.Project, true
+	case "created":
+		return task.Created, true
+	case "updated":
+		return task.Updated, true
+	case "assignee":
+		if task.Assignee == nil {
+			return nil, true
+		}
+		return *task.Assignee, true
+	case "reporter":
+		return task.Reporter, true
+	case "priority":
+		return task.Priority, true
+	case "transition_count":
+		return len(task.Transitions), true
+	case "workflow":
+		return formatWorkflow(task.Transitions), true
+	}
+
+	if strings.HasPrefix(column, "customfield_") {
+		return task.CustomFields[column], true
+	}
+	return nil, false
+}
+
+// transitionValue returns the value of a transition column
+func transitionValue(transition Transition, column string) (interface{}, bool) {
+	switch column {
+	case "from_status":
+		return transition.FromStatus, true
+	case "to_status":
+		return transition.ToStatus, true
+	case "author":
+		return transition.Author, true
+	case "author_user_name":
+		return transition.AuthorEmail, true
+	case "transition_time":
+		return transition.TransitionTime, true
+	case "transition_time_utc":
+		return transition.TransitionTimeUTC, true
+	}
+	return nil, false
+}
+
+// exportRows flattens the response into rows of column values
+func exportRows(data TransitionCheckResponse, opts ExportOptions) ([]map[string]interface{}, error) {
+	var rows []map[string]interface{}
+
+	for _, task := range data.Tasks {
+		if opts.Rows == "tickets" {
+			row := make(map[string]interface{})
+			for _, column := range opts.Columns {
+				value, ok := ticketValue(task, column)
+				if !ok {
+					return nil, fmt.Errorf("unknown ticket column %q", column)
+				}
+				row[column] = value
+			}
+			rows = append(rows, row)
+			continue
+		}
+
+		// Transition rows carry the ticket columns alongside the transition columns
+		for _, transition := range sortTransitions(task.Transitions) {
+			row := make(map[string]interface{})
+			for _, column := range opts.Columns {
+				value, ok := transitionValue(transition, column)
+				if !ok {
+					value, ok = ticketValue(task, column)
+				}
+				if !ok {
+					return nil, fmt.Errorf("unknown transition column %q", column)
+				}
+				row[column] = value
+			}
+			rows = append(rows, row)
+		}
+	}
+
+	return rows, nil
+}
+
+// csvCell renders a column value as a CSV cell; JIRA option objects collapse to their display value
+func csvCell(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case map[string]interface{}:
+		for _, field := range []string{"value", "name", "displayName", "key"} {
+			if s, ok := v[field].(string); ok {
+				return s
+			}
+		}
+	case []interface{}:
+		var cells []string
+		for _, item := range v {
+			cells = append(cells, csvCell(item))
+		}
+		return strings.Join(cells, ";")
+	}
+
+	if jsonBytes, err := json.Marshal(value); err == nil {
+		return string(jsonBytes)
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+// RenderOutput serializes the response in the requested format
+func RenderOutput(data TransitionCheckResponse, opts ExportOptions) ([]byte, error) {
+	if opts.Format == "json" {
+		return json.MarshalIndent(data, "", "  ")
+	}
+
+	if len(opts.Columns) == 0 {
+		opts.Columns = defaultTicketColumns
+		if opts.Rows == "transitions" {
+			opts.Columns = defaultTransitionColumns
+		}
+	}
+
+	rows, err := exportRows(data, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if opts.Format == "jsonl" {
+		encoder := json.NewEncoder(&buf)
+		for _, row := range rows {
+			if err := encoder.Encode(row); err != nil {
+				return nil, err
+			}
+		}
+		return buf.Bytes(), nil
+	}
+
+	writer := csv.NewWriter(&buf)
+	if err := writer.Write(opts.Columns); err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		record := make([]string, len(opts.Columns))
+		for i, column := range opts.Columns {
+			record[i] = csvCell(row[column])
+		}
+		if err := writer.Write(record); err != nil {
+			return nil, err
+		}
+	}
+	writer.Flush()
+	return buf.Bytes(), writer.Error()
+}
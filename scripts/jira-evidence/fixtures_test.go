@@ -0,0 +1,22 @@
+package main
+
+// statusChange returns a transition made by author at a JIRA timestamp
+func statusChange(from, to, author, at string) Transition {
+	return Transition{FromStatus: from, ToStatus: to, Author: author, TransitionTime: at}
+}
+
+// storyTicket returns a retrieved story created on 2024-01-01 at 09:00 UTC, in status with the given transitions
+func storyTicket(key, status string, transitions ...Transition) JiraTransitionResult {
+	return JiraTransitionResult{
+		Key:         key,
+		Type:        "Story",
+		Status:      status,
+		Created:     "2024-01-01T09:00:00.000+0000",
+		Transitions: transitions,
+	}
+}
+
+// commitBy returns a commit by author referencing the given ticket keys
+func commitBy(hash, author, email string, keys ...string) Commit {
+	return Commit{Hash: hash, Author: author, AuthorEmail: email, JiraIDs: keys}
+}
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// defaultVersionTemplate names the JIRA version after the image and its version
+const defaultVersionTemplate = "{{.ImageName}} {{.ImageVersion}}"
+
+// projectVersion is the subset of a JIRA project version used here
+type projectVersion struct {
+	ID          string `json:"id,omitempty"`
+	Name        string `json:"name,omitempty"`
+	ProjectID   int    `json:"projectId,omitempty"`
+	Description string `json:"description,omitempty"`
+	Released    bool   `json:"released"`
+	ReleaseDate string `json:"releaseDate,omitempty"`
+}
+
+// versionName renders the version name template for a project
+func versionName(tmpl *template.Template, build *BuildInfo, project string) (string, error) {
+	var name strings.Builder
+	values := struct {
+		BuildInfo
+		Project string
+	}{*build, project}
+	if err := tmpl.Execute(&name, values); err != nil {
+		return "", fmt.Errorf("failed to render version name: %v", err)
+	}
+	if strings.TrimSpace(name.String()) == "" {
+		return "", fmt.Errorf("version name template rendered an empty name")
+	}
+	return strings.TrimSpace(name.String()), nil
+}
+
+// EnsureVersion returns the project version with the given name, creating it if needed.
+// The boolean reports whether the version was created.
+func (jc *JiraClient) EnsureVersion(project, name, description string) (projectVersion, bool, error) {
+	ctx := context.Background()
+
+	var versions []projectVersion
+	if err := jc.do(ctx, http.MethodGet, fmt.Sprintf("rest/api/3/project/%s/versions", url.PathEscape(project)), nil, &versions); err != nil {
+		return projectVersion{}, false, err
+	}
+	for _, version := range versions {
+		if version.Name == name {
+			return version, false, nil
+		}
+	}
+
+	var projectInfo struct {
+		ID string `json:"id"`
+	}
+	if err := jc.do(ctx, http.MethodGet, fmt.Sprintf("rest/api/3/project/%s", url.PathEscape(project)), nil, &projectInfo); err != nil {
+		return projectVersion{}, false, err
+	}
+	var projectID int
+	if _, err := fmt.Sscan(projectInfo.ID, &projectID); err != nil {
+		return projectVersion{}, false, fmt.Errorf("unexpected project id %q for %s", projectInfo.ID, project)
+	}
+
+	created := projectVersion{}
+	request := projectVersion{Name: name, ProjectID: projectID, Description: description}
+	if err := jc.do(ctx, http.MethodPost, "rest/api/3/version", request, &created); err != nil {
+		return projectVersion{}, false, err
+	}
+	return created, true, nil
+}
+
+// AddFixVersion adds the version to the ticket's fixVersions, keeping the versions already set
+func (jc *JiraClient) AddFixVersion(key, name string) error {
+	update := map[string]interface{}{
+		"update": map[string]interface{}{
+			"fixVersions": []interface{}{
+				map[string]interface{}{"add": map[string]string{"name": name}},
+			},
+		},
+	}
+	return jc.do(context.Background(), http.MethodPut, fmt.Sprintf("rest/api/3/issue/%s", url.PathEscape(key)), update, nil)
+}
+
+// ReleaseVersion marks the version released on the given date (YYYY-MM-DD)
+func (jc *JiraClient) ReleaseVersion(id, date string) error {
+	update := map[string]interface{}{"released": true, "releaseDate": date}
+	return jc.do(context.Background(), http.MethodPut, fmt.Sprintf("rest/api/3/version/%s", url.PathEscape(id)), update, nil)
+}
+
+// runFixVersion implements the fix-version subcommand: ensure a release version per project,
+// add it to every retrieved ticket and optionally mark it released
+func runFixVersion(args []string) int {
+	fs := flag.NewFlagSet("fix-version", flag.ExitOnError)
+	evidenceFile := fs.String("evidence", "", "Evidence JSON file written by the tool (default: $OUTPUT_FILE or transformed_jira_data.json)")
+	nameTemplate := fs.String("version-template", "", "Version name template (default: $JIRA_VERSION_TEMPLATE or '"+defaultVersionTemplate+"')")
+	release := fs.Bool("release", false, "Mark the version released")
+	releaseDate := fs.String("release-date", "", "Release date YYYY-MM-DD (default: $BUILD_DATE, then today in UTC)")
+	build := registerBuildFlags(fs)
+	dryRun := registerDryRunFlags(fs)
+	fs.Parse(args)
+
+	if err := build.resolve(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return exitUsage
+	}
+	if *nameTemplate == "" {
+		*nameTemplate = os.Getenv("JIRA_VERSION_TEMPLATE")
+		if *nameTemplate == "" {
+			*nameTemplate = defaultVersionTemplate
+		}
+	}
+	tmpl, err := template.New("version").Parse(*nameTemplate)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: invalid version template: %v\n", err)
+		return exitUsage
+	}
+	if *releaseDate == "" {
+		*releaseDate = os.Getenv("BUILD_DATE")
+		if *releaseDate == "" {
+			*releaseDate = time.Now().UTC().Format("2006-01-02")
+		}
+	}
+	if _, err := time.Parse("2006-01-02", *releaseDate); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: invalid release date %q, expected YYYY-MM-DD\n", *releaseDate)
+		return exitUsage
+	}
+
+	data, err := readEvidence(evidencePath(*evidenceFile))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return exitError
+	}
+
+	// Versions belong to a project, so tickets are grouped by project
+	ticketsByProject := make(map[string][]string)
+	for _, task := range data.Tasks {
+		if task.Type == "Error" || task.Project == "" {
+			continue
+		}
+		ticketsByProject[task.Project] = append(ticketsByProject[task.Project], task.Key)
+	}
+	projects := make([]string, 0, len(ticketsByProject))
+	for project := range ticketsByProject {
+		projects = append(projects, project)
+	}
+	sort.Strings(projects)
+
+	// Render every version name before the first write, so a template error changes nothing
+	names := make(map[string]string, len(projects))
+	for _, project := range projects {
+		if names[project], err = versionName(tmpl, build, project); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return exitUsage
+		}
+	}
+
+	jiraClient, err := dryRun.NewJiraClient()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating JIRA client: %v\n", err)
+		return exitError
+	}
+
+	failed := 0
+	for _, project := range projects {
+		name := names[project]
+		version, created, err := jiraClient.EnsureVersion(project, name, fmt.Sprintf("Build %s of %s", build.BuildNumber, build.Package()))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %s: failed to ensure version %q: %v\n", project, name, err)
+			failed += len(ticketsByProject[project])
+			continue
+		}
+		if created {
+			fmt.Printf("✅ %s: created version %q\n", project, name)
+		} else {
+			fmt.Printf("✅ %s: version %q already exists\n", project, name)
+		}
+
+		projectFailed := 0
+		for _, key := range ticketsByProject[project] {
+			if err := jiraClient.AddFixVersion(key, name); err != nil {
+				fmt.Fprintf(os.Stderr, "❌ %s: failed to add fix version: %v\n", key, err)
+				projectFailed++
+				continue
+			}
+			fmt.Printf("✅ %s: fix version %q\n", key, name)
+		}
+		failed += projectFailed
+
+		// A version is only released once every ticket of the project carries it
+		if *release && !version.Released && projectFailed > 0 {
+			fmt.Fprintf(os.Stderr, "⚠️  %s: not releasing version %q, %d ticket(s) could not be updated\n", project, name, projectFailed)
+		} else if *release && !version.Released {
+			if err := jiraClient.ReleaseVersion(version.ID, *releaseDate); err != nil {
+				fmt.Fprintf(os.Stderr, "❌ %s: failed to release version %q: %v\n", project, name, err)
+				failed++
+				continue
+			}
+			fmt.Printf("✅ %s: released version %q on %s\n", project, name, *releaseDate)
+		}
+	}
+
+	if err := dryRun.finish(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return exitError
+	}
+
+	if failed > 0 {
+		fmt.Fprintf(os.Stderr, "❌ %d fix version update(s) failed\n", failed)
+		return exitError
+	}
+	return exitSuccess
+}
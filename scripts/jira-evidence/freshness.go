@@ -0,0 +1,101 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// Policy names recorded for the ticket freshness checks
+const (
+	createdAfterCommitPolicy   = "ticket-created-after-commit"
+	resolvedBeforeCommitPolicy = "ticket-resolved-before-commit"
+	staleTicketPolicy          = "ticket-stale"
+)
+
+// FreshnessOptions configures the date-based checks between tickets and the commits that reference them
+type FreshnessOptions struct {
+	CheckCommitDates bool // flag tickets created after, or resolved before, a referencing commit
+	StaleDays        int  // flag tickets not updated for more than this many days; 0 disables the check
+	Enforce          bool // fail the run on findings instead of reporting warnings
+}
+
+// checkFreshness evaluates the configured freshness checks for every retrieved ticket
+func checkFreshness(data TransitionCheckResponse, opts FreshnessOptions, now time.Time) []PolicyResult {
+	severity := severityWarning
+	if opts.Enforce {
+		severity = severityError
+	}
+
+	commitsByKey := commitsByTicket(data)
+
+	var results []PolicyResult
+	for _, task := range data.Tasks {
+		if task.Type == "Error" {
+			continue
+		}
+
+		if opts.CheckCommitDates {
+			if commits := commitsByKey[task.Key]; len(commits) > 0 {
+				results = append(results, checkCommitDates(task, commits, severity)...)
+			} else {
+				results = append(results,
+					notEvaluated(createdAfterCommitPolicy, task.Key, severity, "no commit references this ticket"),
+					notEvaluated(resolvedBeforeCommitPolicy, task.Key, severity, "no commit references this ticket"))
+			}
+		}
+
+		if opts.StaleDays > 0 {
+			result := PolicyResult{Policy: staleTicketPolicy, Key: task.Key, Severity: severity}
+			updated, err := parseJiraTime(task.Updated)
+			if err != nil {
+				result.Message = fmt.Sprintf("cannot parse updated date %q", task.Updated)
+			} else if age := now.Sub(updated); age > time.Duration(opts.StaleDays)*24*time.Hour {
+				result.Message = fmt.Sprintf("last updated %d days ago, more than %d days", int(age.Hours()/24), opts.StaleDays)
+			} else {
+				result.Passed = true
+				result.Message = fmt.Sprintf("last updated %d days ago", int(age.Hours()/24))
+			}
+			results = append(results, result)
+		}
+	}
+
+	return results
+}
+
+// checkCommitDates compares the ticket's created and resolved dates with the authored dates of its commits.
+// Either finding usually means a commit message references the wrong key.
+func checkCommitDates(task JiraTransitionResult, commits []Commit, severity string) []PolicyResult {
+	created, createdErr := parseJiraTime(task.Created)
+	resolved, resolvedErr := parseJiraTime(task.Resolved)
+
+	createdResult := PolicyResult{Policy: createdAfterCommitPolicy, Key: task.Key, Passed: true, Severity: severity,
+		Message: "ticket was created before all referencing commits"}
+	resolvedResult := PolicyResult{Policy: resolvedBeforeCommitPolicy, Key: task.Key, Passed: true, Severity: severity,
+		Message: "ticket was not resolved before any referencing commit"}
+
+	for _, commit := range commits {
+		authored, err := parseJiraTime(commit.Date)
+		if err != nil {
+			continue
+		}
+		if createdErr == nil && createdResult.Passed && created.After(authored) {
+			createdResult.Passed = false
+			createdResult.Message = fmt.Sprintf("ticket created %s, after commit %s was authored %s",
+				task.Created, shortHash(commit.Hash), commit.Date)
+		}
+		if resolvedErr == nil && resolvedResult.Passed && resolved.Before(authored) {
+			resolvedResult.Passed = false
+			resolvedResult.Message = fmt.Sprintf("ticket resolved %s, before commit %s was authored %s",
+				task.Resolved, shortHash(commit.Hash), commit.Date)
+		}
+	}
+
+	results := []PolicyResult{}
+	if createdErr == nil {
+		results = append(results, createdResult)
+	}
+	if resolvedErr == nil {
+		results = append(results, resolvedResult)
+	}
+	return results
+}
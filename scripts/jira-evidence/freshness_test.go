@@ -0,0 +1,130 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCheckFreshnessStaleThreshold(t *testing.T) {
+	now := time.Date(2024, 3, 31, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name       string
+		updated    string
+		wantPassed bool
+		wantInMsg  string
+	}{
+		{"updated today", "2024-03-31T08:00:00.000+0000", true, "last updated 0 days ago"},
+		{"exactly at the threshold", "2024-03-01T12:00:00.000+0000", true, "last updated 30 days ago"},
+		{"one second over the threshold", "2024-03-01T11:59:59.000+0000", false, "more than 30 days"},
+		{"threshold crossed through the offset", "2024-03-01T13:00:00.000+0200", false, "more than 30 days"},
+		{"unparseable update date", "", false, "cannot parse updated date"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := storyTicket("EV-1", "Done")
+			task.Updated = tt.updated
+			data := TransitionCheckResponse{Tasks: []JiraTransitionResult{task}}
+
+			results := checkFreshness(data, FreshnessOptions{StaleDays: 30}, now)
+			if len(results) != 1 {
+				t.Fatalf("got %d results, want 1", len(results))
+			}
+			result := results[0]
+			if result.Passed != tt.wantPassed || result.Policy != staleTicketPolicy || result.Severity != severityWarning {
+				t.Errorf("result = %+v, want passed %v as a warning", result, tt.wantPassed)
+			}
+			if !strings.Contains(result.Message, tt.wantInMsg) {
+				t.Errorf("message %q does not contain %q", result.Message, tt.wantInMsg)
+			}
+		})
+	}
+}
+
+func TestCheckFreshnessCommitDates(t *testing.T) {
+	// The ticket exists from 2024-01-01 09:00 UTC and is resolved on 2024-01-10 09:00 UTC
+	task := storyTicket("EV-1", "Done")
+	task.Resolved = "2024-01-10T09:00:00.000+0000"
+	authoredAt := func(date string) Commit {
+		commit := commitBy("abc1234def", "Jane Doe", "", "EV-1")
+		commit.Date = date
+		return commit
+	}
+
+	tests := []struct {
+		name           string
+		commitDate     string
+		wantCreatedOK  bool
+		wantResolvedOK bool
+	}{
+		{"authored while the ticket was open", "2024-01-05T10:00:00+02:00", true, true},
+		{"authored before the ticket was created", "2023-12-31T10:00:00+00:00", false, true},
+		{"authored just before creation, by offset", "2024-01-01T10:30:00+02:00", false, true},
+		{"authored after the ticket was resolved", "2024-01-11T10:00:00+00:00", true, false},
+		{"unparseable commit date", "last week", true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := TransitionCheckResponse{Tasks: []JiraTransitionResult{task}, Commits: []Commit{authoredAt(tt.commitDate)}}
+
+			results := checkFreshness(data, FreshnessOptions{CheckCommitDates: true, Enforce: true}, time.Now())
+			if len(results) != 2 {
+				t.Fatalf("got %d results, want created and resolved checks", len(results))
+			}
+			if results[0].Policy != createdAfterCommitPolicy || results[0].Passed != tt.wantCreatedOK {
+				t.Errorf("created check = %+v, want passed %v", results[0], tt.wantCreatedOK)
+			}
+			if results[1].Policy != resolvedBeforeCommitPolicy || results[1].Passed != tt.wantResolvedOK {
+				t.Errorf("resolved check = %+v, want passed %v", results[1], tt.wantResolvedOK)
+			}
+			for _, result := range results {
+				if result.Severity != severityError {
+					t.Errorf("severity = %q, want %q when enforced", result.Severity, severityError)
+				}
+			}
+		})
+	}
+}
+
+func TestCheckFreshnessSkipsTickets(t *testing.T) {
+	unresolved := storyTicket("EV-1", "In Progress")
+	unretrieved := JiraTransitionResult{Key: "EV-2", Type: "Error"}
+	commit := commitBy("abc1234def", "Jane Doe", "", "EV-1", "EV-2")
+	commit.Date = "2024-01-05T10:00:00+00:00"
+	data := TransitionCheckResponse{Tasks: []JiraTransitionResult{unresolved, unretrieved}, Commits: []Commit{commit}}
+
+	results := checkFreshness(data, FreshnessOptions{CheckCommitDates: true}, time.Now())
+	if len(results) != 1 || results[0].Key != "EV-1" || results[0].Policy != createdAfterCommitPolicy {
+		t.Errorf("results = %+v, want only the created check of the unresolved ticket", results)
+	}
+}
+
+func TestCheckFreshnessWithoutCommits(t *testing.T) {
+	data := TransitionCheckResponse{Tasks: []JiraTransitionResult{storyTicket("EV-1", "Done")}}
+
+	results := checkFreshness(data, FreshnessOptions{CheckCommitDates: true}, time.Now())
+	if len(results) != 2 {
+		t.Fatalf("results = %+v, want created and resolved checks", results)
+	}
+	for _, result := range results {
+		if result.Passed || !strings.HasPrefix(result.Message, "not evaluated") || result.Severity != severityWarning {
+			t.Errorf("result = %+v, want a failed not evaluated warning", result)
+		}
+	}
+}
+
+func TestCheckFreshnessPullRequestKeys(t *testing.T) {
+	// Only the pull request references the ticket, and its commit predates the ticket
+	commit := commitBy("abc1234def", "Jane Doe", "")
+	commit.Date = "2023-12-31T10:00:00+00:00"
+	data := TransitionCheckResponse{
+		Tasks:        []JiraTransitionResult{storyTicket("EV-1", "In Progress")},
+		Commits:      []Commit{commit},
+		PullRequests: []PullRequest{{Number: 7, Commits: []string{"abc1234def"}, JiraIDs: []string{"EV-1"}}},
+	}
+
+	results := checkFreshness(data, FreshnessOptions{CheckCommitDates: true}, time.Now())
+	if len(results) != 1 || results[0].Policy != createdAfterCommitPolicy || results[0].Passed {
+		t.Errorf("results = %+v, want a failed created check through the pull request", results)
+	}
+}
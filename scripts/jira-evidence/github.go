@@ -0,0 +1,195 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// githubIDRegex matches #123, GH-123 and cross-repository owner/repo#123 references
+const githubIDRegex = `(?:[A-Za-z0-9_.-]+/[A-Za-z0-9_.-]+)?#[0-9]+|GH-[0-9]+`
+
+// githubKeyPattern splits a normalized key into its optional repository and the issue number
+var githubKeyPattern = regexp.MustCompile(`^(?:([A-Za-z0-9_.-]+/[A-Za-z0-9_.-]+))?#([0-9]+)$`)
+
+// normalizeGitHubID maps GH-123 to #123 so both reference styles name the same issue
+func normalizeGitHubID(reference string) string {
+	if strings.HasPrefix(reference, "GH-") {
+		return "#" + strings.TrimPrefix(reference, "GH-")
+	}
+	return reference
+}
+
+// GitHubClient reads issues from the GitHub REST API
+type GitHubClient struct {
+	restClient
+	repository string
+}
+
+// githubUser is the subset of a GitHub user used here
+type githubUser struct {
+	Login string `json:"login"`
+}
+
+// githubIssue is the subset of a GitHub issue used here
+type githubIssue struct {
+	Number      int          `json:"number"`
+	Title       string       `json:"title"`
+	Body        string       `json:"body"`
+	State       string       `json:"state"`
+	StateReason string       `json:"state_reason"`
+	User        githubUser   `json:"user"`
+	Assignees   []githubUser `json:"assignees"`
+	Labels      []struct {
+		Name string `json:"name"`
+	} `json:"labels"`
+	Milestone *struct {
+		Title string `json:"title"`
+	} `json:"milestone"`
+	CreatedAt   string    `json:"created_at"`
+	UpdatedAt   string    `json:"updated_at"`
+	ClosedAt    string    `json:"closed_at"`
+	PullRequest *struct{} `json:"pull_request"`
+}
+
+// githubEvent is the subset of a GitHub issue event used here
+type githubEvent struct {
+	Event     string     `json:"event"`
+	Actor     githubUser `json:"actor"`
+	CreatedAt string     `json:"created_at"`
+}
+
+// NewGitHubClient creates a GitHub client from GITHUB_TOKEN, GITHUB_REPOSITORY and GITHUB_API_URL
+func NewGitHubClient() (*GitHubClient, error) {
+	token := os.Getenv("GITHUB_TOKEN")
+	if token == "" {
+		return nil, fmt.Errorf("GitHub token not found, set GITHUB_TOKEN variable")
+	}
+	repository := os.Getenv("GITHUB_REPOSITORY")
+	if repository == "" {
+		return nil, fmt.Errorf("GitHub repository not found, set GITHUB_REPOSITORY variable (owner/repo)")
+	}
+	baseURL := os.Getenv("GITHUB_API_URL")
+	if baseURL == "" {
+		baseURL = "https://api.github.com"
+	}
+
+	headers := bearerHeaders(token)
+	headers["Accept"] = "application/vnd.github+json"
+	headers["X-GitHub-Api-Version"] = "2022-11-28"
+	return &GitHubClient{
+		restClient: newRESTClient(baseURL, headers),
+		repository: repository,
+	}, nil
+}
+
+// issueEvents returns every event of an issue, following pagination
+func (gc *GitHubClient) issueEvents(repository string, number string) ([]githubEvent, error) {
+	return fetchPages(func(page int) ([]githubEvent, error) {
+		var batch []githubEvent
+		path := fmt.Sprintf("/repos/%s/issues/%s/events?per_page=%d&page=%d", repository, number, pageSize, page)
+		return batch, gc.get(path, &batch)
+	})
+}
+
+// FetchDetails implements IssueTracker for GitHub Issues. Keys are #123 for the current repository
+// or owner/repo#123; closed and reopened events become transitions between Open and Closed.
+func (gc *GitHubClient) FetchDetails(ids []string) TransitionCheckResponse {
+	return fetchEach(ids, gc.fetchIssue)
+}
+
+// dropPullRequests removes the tasks a #123 reference resolved to a pull request, such as the one in
+// "Merge pull request #123 from ..." or a squash-merge subject. The issues API serves pull requests too,
+// but they are not tickets.
+func dropPullRequests(data *TransitionCheckResponse) {
+	dropped := make(map[string]bool)
+	tasks := data.Tasks[:0]
+	for _, task := range data.Tasks {
+		if task.Type == "Pull Request" {
+			fmt.Fprintf(os.Stderr, "Skipping %s: it is a pull request, not an issue\n", task.Key)
+			dropped[task.Key] = true
+			continue
+		}
+		tasks = append(tasks, task)
+	}
+	if len(dropped) == 0 {
+		return
+	}
+	data.Tasks = tasks
+
+	requested := make([]string, 0, len(data.TicketRequested))
+	for _, id := range data.TicketRequested {
+		if !dropped[id] {
+			requested = append(requested, id)
+		}
+	}
+	data.TicketRequested = requested
+}
+
+// fetchIssue retrieves one issue and its state events
+func (gc *GitHubClient) fetchIssue(id string) (JiraTransitionResult, error) {
+	match := githubKeyPattern.FindStringSubmatch(id)
+	if match == nil {
+		return JiraTransitionResult{}, fmt.Errorf("not a GitHub issue reference: %q", id)
+	}
+	repository, number := match[1], match[2]
+	if repository == "" {
+		repository = gc.repository
+	}
+
+	var issue githubIssue
+	if err := gc.get(fmt.Sprintf("/repos/%s/issues/%s", repository, url.PathEscape(number)), &issue); err != nil {
+		return JiraTransitionResult{}, err
+	}
+	events, err := gc.issueEvents(repository, number)
+	if err != nil {
+		return JiraTransitionResult{}, err
+	}
+
+	status, category := openClosedStatus(issue.State)
+	task := JiraTransitionResult{
+		Key:            id,
+		Summary:        issue.Title,
+		Status:         status,
+		StatusCategory: category,
+		Description:    issue.Body,
+		Type:           "Issue",
+		Project:        repository,
+		Created:        issue.CreatedAt,
+		Updated:        issue.UpdatedAt,
+		Resolved:       issue.ClosedAt,
+		Reporter:       issue.User.Login,
+		Priority:       "",
+		Transitions:    []Transition{},
+	}
+	if issue.PullRequest != nil {
+		task.Type = "Pull Request"
+	}
+
+	var assignees []string
+	for _, assignee := range issue.Assignees {
+		assignees = append(assignees, assignee.Login)
+	}
+	if len(assignees) > 0 {
+		joined := strings.Join(assignees, ", ")
+		task.Assignee = &joined
+	}
+	for _, label := range issue.Labels {
+		task.Labels = append(task.Labels, label.Name)
+	}
+	if issue.Milestone != nil {
+		task.CustomFields = map[string]interface{}{"milestone": issue.Milestone.Title}
+	}
+
+	for _, event := range events {
+		if transition, ok := openClosedTransition(event.Event, event.Actor.Login, event.CreatedAt); ok {
+			task.Transitions = append(task.Transitions, transition)
+		}
+	}
+	task.Transitions = sortTransitions(task.Transitions)
+	task.Timeline = buildTimeline(task)
+
+	return task, nil
+}
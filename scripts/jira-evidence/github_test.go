@@ -0,0 +1,24 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDropPullRequests(t *testing.T) {
+	issue := storyTicket("#12", "Closed")
+	pull := storyTicket("#123", "Closed")
+	pull.Type = "Pull Request"
+	data := TransitionCheckResponse{
+		TicketRequested: []string{"#12", "#123", "#7"},
+		Tasks:           []JiraTransitionResult{issue, pull, {Key: "#7", Type: "Error"}},
+	}
+
+	dropPullRequests(&data)
+	if got := strings.Join(data.TicketRequested, ","); got != "#12,#7" {
+		t.Errorf("ticketRequested = %s, want #12,#7", got)
+	}
+	if len(data.Tasks) != 2 || data.Tasks[0].Key != "#12" || data.Tasks[1].Key != "#7" {
+		t.Errorf("tasks = %+v, want the issue and the error task", data.Tasks)
+	}
+}
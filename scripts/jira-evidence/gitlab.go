@@ -0,0 +1,151 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// gitlabIDRegex matches #123 and cross-project group/project#123 references, including subgroups
+const gitlabIDRegex = `(?:[A-Za-z0-9_.-]+(?:/[A-Za-z0-9_.-]+)+)?#[0-9]+`
+
+// gitlabKeyPattern splits a key into its optional project path and the issue IID
+var gitlabKeyPattern = regexp.MustCompile(`^(?:([A-Za-z0-9_.-]+(?:/[A-Za-z0-9_.-]+)+))?#([0-9]+)$`)
+
+// GitLabClient reads issues from the GitLab REST API
+type GitLabClient struct {
+	restClient
+	project string
+}
+
+// gitlabUser is the subset of a GitLab user used here
+type gitlabUser struct {
+	Username string `json:"username"`
+}
+
+// gitlabIssue is the subset of a GitLab issue used here
+type gitlabIssue struct {
+	IID         int          `json:"iid"`
+	Title       string       `json:"title"`
+	Description string       `json:"description"`
+	State       string       `json:"state"`
+	IssueType   string       `json:"issue_type"`
+	Author      gitlabUser   `json:"author"`
+	Assignees   []gitlabUser `json:"assignees"`
+	Labels      []string     `json:"labels"`
+	Milestone   *struct {
+		Title string `json:"title"`
+	} `json:"milestone"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+	ClosedAt  string `json:"closed_at"`
+}
+
+// gitlabStateEvent is the subset of a GitLab resource state event used here
+type gitlabStateEvent struct {
+	State     string     `json:"state"`
+	User      gitlabUser `json:"user"`
+	CreatedAt string     `json:"created_at"`
+}
+
+// NewGitLabClient creates a GitLab client from GITLAB_TOKEN, GITLAB_PROJECT and GITLAB_API_URL
+func NewGitLabClient() (*GitLabClient, error) {
+	token := os.Getenv("GITLAB_TOKEN")
+	if token == "" {
+		return nil, fmt.Errorf("GitLab token not found, set GITLAB_TOKEN variable")
+	}
+	baseURL := os.Getenv("GITLAB_API_URL")
+	if baseURL == "" {
+		baseURL = "https://gitlab.com/api/v4"
+	}
+
+	return &GitLabClient{
+		restClient: newRESTClient(baseURL, map[string]string{"PRIVATE-TOKEN": token}),
+		project:    os.Getenv("GITLAB_PROJECT"),
+	}, nil
+}
+
+// stateEvents returns every resource state event of an issue, following pagination
+func (gc *GitLabClient) stateEvents(projectPath string, iid string) ([]gitlabStateEvent, error) {
+	return fetchPages(func(page int) ([]gitlabStateEvent, error) {
+		var batch []gitlabStateEvent
+		path := fmt.Sprintf("%s/issues/%s/resource_state_events?per_page=%d&page=%d", projectPath, iid, pageSize, page)
+		return batch, gc.get(path, &batch)
+	})
+}
+
+// FetchDetails implements IssueTracker for GitLab Issues. Keys are #123 for the GITLAB_PROJECT project
+// or group/project#123; closed and reopened state events become transitions between Open and Closed.
+func (gc *GitLabClient) FetchDetails(ids []string) TransitionCheckResponse {
+	return fetchEach(ids, gc.fetchIssue)
+}
+
+// fetchIssue retrieves one issue and its state events
+func (gc *GitLabClient) fetchIssue(id string) (JiraTransitionResult, error) {
+	match := gitlabKeyPattern.FindStringSubmatch(id)
+	if match == nil {
+		return JiraTransitionResult{}, fmt.Errorf("not a GitLab issue reference: %q", id)
+	}
+	project, iid := match[1], match[2]
+	if project == "" {
+		project = gc.project
+	}
+	if project == "" {
+		return JiraTransitionResult{}, fmt.Errorf("%s has no project, set GITLAB_PROJECT variable (group/project)", id)
+	}
+	projectPath := "/projects/" + url.PathEscape(project)
+
+	var issue gitlabIssue
+	if err := gc.get(fmt.Sprintf("%s/issues/%s", projectPath, iid), &issue); err != nil {
+		return JiraTransitionResult{}, err
+	}
+	events, err := gc.stateEvents(projectPath, iid)
+	if err != nil {
+		return JiraTransitionResult{}, err
+	}
+
+	status, category := openClosedStatus(issue.State)
+	task := JiraTransitionResult{
+		Key:            id,
+		Summary:        issue.Title,
+		Status:         status,
+		StatusCategory: category,
+		Description:    issue.Description,
+		Type:           "Issue",
+		Project:        project,
+		Created:        issue.CreatedAt,
+		Updated:        issue.UpdatedAt,
+		Resolved:       issue.ClosedAt,
+		Reporter:       issue.Author.Username,
+		Priority:       "",
+		Transitions:    []Transition{},
+		Labels:         issue.Labels,
+	}
+	if issue.IssueType == "incident" {
+		task.Type = "Incident"
+	}
+
+	var assignees []string
+	for _, assignee := range issue.Assignees {
+		assignees = append(assignees, assignee.Username)
+	}
+	if len(assignees) > 0 {
+		joined := strings.Join(assignees, ", ")
+		task.Assignee = &joined
+	}
+	if issue.Milestone != nil {
+		task.CustomFields = map[string]interface{}{"milestone": issue.Milestone.Title}
+	}
+
+	for _, event := range events {
+		if transition, ok := openClosedTransition(event.State, event.User.Username, event.CreatedAt); ok {
+			task.Transitions = append(task.Transitions, transition)
+		}
+	}
+	task.Transitions = sortTransitions(task.Transitions)
+	task.Timeline = buildTimeline(task)
+
+	return task, nil
+}
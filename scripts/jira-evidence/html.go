@@ -0,0 +1,173 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// htmlReportTemplate is a self-contained page (inline CSS, no external assets) so it can be attached as a single evidence file
+const htmlReportTemplate = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Jira Evidence Report</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em; color: #172b4d; }
+h1, h2, h3 { color: #0747a6; }
+table { border-collapse: collapse; width: 100%; margin-bottom: 1.5em; }
+th, td { border: 1px solid #dfe1e6; padding: 6px 10px; text-align: left; vertical-align: top; }
+th { background: #f4f5f7; }
+.summary { display: flex; gap: 1em; margin-bottom: 1.5em; }
+.card { border: 1px solid #dfe1e6; border-radius: 4px; padding: 0.75em 1.25em; }
+.card .value { font-size: 1.6em; font-weight: bold; }
+.pass { color: #006644; font-weight: bold; }
+.fail { color: #bf2600; font-weight: bold; }
+.warn { color: #974f0c; font-weight: bold; }
+.error { background: #ffebe6; }
+code { font-family: SFMono-Regular, Consolas, monospace; font-size: 0.9em; }
+</style>
+</head>
+<body>
+<h1>Jira Evidence Report</h1>
+<p>Generated at {{.GeneratedAt}}</p>
+
+<div class="summary">
+  <div class="card"><div class="value">{{len .Data.TicketRequested}}</div>Tickets requested</div>
+  <div class="card"><div class="value">{{.Retrieved}}</div>Tickets retrieved</div>
+  <div class="card"><div class="value">{{.Errors}}</div>Retrieval errors</div>
+  <div class="card"><div class="value">{{len .Data.Commits}}</div>Commits</div>
+  {{if .Data.Summary}}<div class="card"><div class="value">{{.Data.Summary.SecurityTickets}}</div>Security-sensitive</div>{{end}}
+  <div class="card"><div class="value">{{if .Data.Policy}}{{if .Data.Policy.Passed}}<span class="pass">PASS</span>{{else}}<span class="fail">FAIL</span>{{end}}{{else}}N/A{{end}}</div>Policy</div>
+</div>
+
+<h2>Tickets</h2>
+<table>
+<tr><th>Key</th><th>Summary</th><th>Type</th><th>Status</th><th>Priority</th><th>Assignee</th><th>Workflow</th></tr>
+{{range .Data.Tasks}}<tr{{if eq .Type "Error"}} class="error"{{end}}>
+<td><a href="#{{.Key}}">{{.Key}}</a></td><td>{{.Description}}</td><td>{{.Type}}</td><td>{{.Status}}</td><td>{{.Priority}}</td><td>{{if .Assignee}}{{deref .Assignee}}{{else}}Unassigned{{end}}</td><td>{{workflow .}}</td>
+</tr>
+{{end}}</table>
+
+<h2>Workflow Timelines</h2>
+{{range .Data.Tasks}}<h3 id="{{.Key}}">{{.Key}}</h3>
+{{if and .Timeline .Timeline.Periods}}<p>Cycle time: {{duration .Timeline.CycleTimeSeconds}} · Lead time: {{duration .Timeline.LeadTimeSeconds}} · Reopened: {{.Timeline.ReopenCount}}</p>
+<table>
+<tr><th>Status</th><th>Entered</th><th>Exited</th><th>Time in Status</th><th>Moved By</th></tr>
+{{range .Timeline.Periods}}<tr><td>{{.Status}}</td><td>{{.Entered}}</td><td>{{if .Exited}}{{.Exited}}{{else}}current{{end}}</td><td>{{duration .DurationSeconds}}</td><td>{{.EnteredBy}}</td></tr>
+{{end}}</table>
+{{else}}<p>No transitions available</p>
+{{end}}{{with index $.CommitsByKey .Key}}<p>Commits:{{range .}} <code>{{shortHash .Hash}}</code>{{end}}</p>
+{{end}}{{end}}
+
+<h2>Commit Attribution</h2>
+{{if .Data.Commits}}<table>
+<tr><th>Commit</th><th>Author</th><th>Date</th><th>Subject</th><th>Tickets</th></tr>
+{{range .Data.Commits}}<tr><td><code>{{shortHash .Hash}}</code></td><td>{{.Author}} &lt;{{.AuthorEmail}}&gt;</td><td>{{.Date}}</td><td>{{.Subject}}</td><td>{{join .JiraIDs ", "}}</td></tr>
+{{end}}</table>
+{{else}}<p>No commit data available</p>
+{{end}}
+{{if .Data.PullRequests}}
+<h2>Pull Requests</h2>
+<table>
+<tr><th>Pull Request</th><th>Author</th><th>Branch</th><th>State</th><th>Reviewers</th><th>Approval</th><th>Tickets</th></tr>
+{{range .Data.PullRequests}}<tr><td><a href="{{.URL}}">#{{.Number}}</a> {{.Title}}</td><td>{{.Author}}</td><td><code>{{.HeadBranch}}</code></td><td>{{.State}}</td><td>{{join .Reviewers ", "}}</td><td>{{.ApprovalState}}{{if .Approvers}} ({{join .Approvers ", "}}){{end}}</td><td>{{join .JiraIDs ", "}}</td></tr>
+{{end}}</table>
+{{end}}
+{{if .Data.PullRequestErrors}}<p>Pull request data is incomplete:</p>
+<ul>{{range .Data.PullRequestErrors}}<li>{{.}}</li>{{end}}</ul>
+{{end}}
+
+<h2>Policy Results</h2>
+{{if .Data.Policy}}<table>
+<tr><th>Policy</th><th>Ticket</th><th>Result</th><th>Message</th></tr>
+{{range .Data.Policy.Results}}<tr><td>{{.Policy}}</td><td>{{.Key}}</td><td>{{if .Passed}}<span class="pass">PASS</span>{{else if eq .Severity "warning"}}<span class="warn">WARN</span>{{else}}<span class="fail">FAIL</span>{{end}}</td><td>{{.Message}}</td></tr>
+{{end}}</table>
+{{else}}<p>No policies evaluated</p>
+{{end}}
+</body>
+</html>
+`
+
+// htmlReportData is the view model rendered by htmlReportTemplate
+type htmlReportData struct {
+	Data         TransitionCheckResponse
+	GeneratedAt  string
+	Retrieved    int
+	Errors       int
+	CommitsByKey map[string][]Commit
+}
+
+// shortHash abbreviates a commit hash for display
+func shortHash(hash string) string {
+	if len(hash) > 12 {
+		return hash[:12]
+	}
+	return hash
+}
+
+// generateHTMLContent generates a self-contained HTML report from JIRA data
+func generateHTMLContent(data TransitionCheckResponse) (string, error) {
+	funcs := template.FuncMap{
+		"workflow": func(task JiraTransitionResult) string {
+			if task.Type == "Error" {
+				return "N/A"
+			}
+			return formatWorkflow(task.Transitions)
+		},
+		"deref":     func(s *string) string { return *s },
+		"shortHash": shortHash,
+		"duration":  formatDuration,
+		"join":      strings.Join,
+	}
+
+	tmpl, err := template.New("report").Funcs(funcs).Parse(htmlReportTemplate)
+	if err != nil {
+		return "", fmt.Errorf("error parsing HTML template: %v", err)
+	}
+
+	view := htmlReportData{
+		Data:         data,
+		GeneratedAt:  time.Now().UTC().Format(time.RFC3339),
+		CommitsByKey: make(map[string][]Commit),
+	}
+	for _, task := range data.Tasks {
+		if task.Type == "Error" {
+			view.Errors++
+		} else {
+			view.Retrieved++
+		}
+	}
+	for _, commit := range data.Commits {
+		for _, jiraID := range commit.JiraIDs {
+			view.CommitsByKey[jiraID] = append(view.CommitsByKey[jiraID], commit)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, view); err != nil {
+		return "", fmt.Errorf("error rendering HTML template: %v", err)
+	}
+	return buf.String(), nil
+}
+
+// GenerateHTMLReport generates and writes the HTML report next to the JSON output
+func GenerateHTMLReport(data TransitionCheckResponse, outputFile string) error {
+	fmt.Println("Step 5: Generating HTML report...")
+
+	content, err := generateHTMLContent(data)
+	if err != nil {
+		return err
+	}
+
+	htmlFile := strings.TrimSuffix(outputFile, filepath.Ext(outputFile)) + ".html"
+	if err := writeToFile(htmlFile, []byte(content)); err != nil {
+		return fmt.Errorf("error writing HTML file: %v", err)
+	}
+
+	fmt.Printf("HTML report saved to: %s\n", htmlFile)
+	return nil
+}
@@ -4,10 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"os"
 	"path/filepath"
 	"reflect"
-	"sort"
 	"strings"
 	"time"
 
@@ -23,12 +23,15 @@ import (
         "tasks": [
             {
                 "key": "EV-1",
+                "summary": "<ticket title>",
                 "status": "QA in Progress",
+                "status_category": "indeterminate",
                 "description": "<description text>",
                 "type": "Task",
                 "project": "EV",
                 "created": "2020-01-01T12:11:56.063+0530",
                 "updated": "2020-01-01T12:12:01.876+0530",
+                "resolved": "2020-01-01T12:12:01.876+0530",
                 "assignee": "<assignee name>",
                 "reporter": "<reporter name>",
                 "priority": "Medium",
@@ -38,7 +41,8 @@ import (
                         "to_status": "In Progress",
                         "author": "<>author name>",
                         "author_user_name": "<author email>",
-                        "transition_time": "2020-07-28T16:39:54.620+0530"
+                        "transition_time": "2020-07-28T16:39:54.620+0530",
+                        "transition_time_utc": "2020-07-28T11:09:54.620Z"
                     }
                 ]
             },
@@ -63,39 +67,92 @@ import (
 */
 
 type TransitionCheckResponse struct {
-	TicketRequested []string               `json:"ticketRequested"`
-	Tasks           []JiraTransitionResult `json:"tasks"`
+	TicketRequested   []string               `json:"ticketRequested"`
+	Tasks             []JiraTransitionResult `json:"tasks"`
+	Commits           []Commit               `json:"commits,omitempty"`
+	PullRequests      []PullRequest          `json:"pullRequests,omitempty"`
+	PullRequestErrors []string               `json:"pullRequestErrors,omitempty"` // failed lookups; pull request data is incomplete when set
+	Summary           *EvidenceSummary       `json:"summary,omitempty"`
+	Policy            *PolicyReport          `json:"policy,omitempty"`
+	Redaction         *RedactionInfo         `json:"redaction,omitempty"`
+	Limits            *LimitsInfo            `json:"limits,omitempty"`
+}
+
+// EvidenceSummary holds aggregate counts over the tasks
+type EvidenceSummary struct {
+	SecurityTickets int `json:"securityTickets"`
+}
+
+// Commit is a git commit from the evidence range and the JIRA IDs referenced in its subject
+type Commit struct {
+	Hash        string   `json:"hash"`
+	Author      string   `json:"author"`
+	AuthorEmail string   `json:"author_email"`
+	Date        string   `json:"date"`
+	Subject     string   `json:"subject"`
+	JiraIDs     []string `json:"jira_ids"`
+}
+
+// PolicyReport holds the outcome of every policy check evaluated over the evidence
+type PolicyReport struct {
+	Passed  bool           `json:"passed"`
+	Results []PolicyResult `json:"results"`
+}
+
+// PolicyResult is a single policy verdict, optionally scoped to one ticket.
+// Failed results with severity "warning" are reported but do not fail the policy.
+type PolicyResult struct {
+	Policy   string `json:"policy"`
+	Key      string `json:"key,omitempty"`
+	Passed   bool   `json:"passed"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
 }
 
 type JiraTransitionResult struct {
-	Key         string       `json:"key"`
-	Status      string       `json:"status"`
-	Description string       `json:"description"`
-	Type        string       `json:"type"`
-	Project     string       `json:"project"`
-	Created     string       `json:"created"`
-	Updated     string       `json:"updated"`
-	Assignee    *string      `json:"assignee"`
-	Reporter    string       `json:"reporter"`
-	Priority    string       `json:"priority"`
-	Transitions []Transition `json:"transitions"`
+	Key             string                 `json:"key"`
+	Summary         string                 `json:"summary,omitempty"`
+	Status          string                 `json:"status"`
+	StatusCategory  string                 `json:"status_category,omitempty"`
+	Description     string                 `json:"description"`
+	Type            string                 `json:"type"`
+	Project         string                 `json:"project"`
+	Created         string                 `json:"created"`
+	Updated         string                 `json:"updated"`
+	Resolved        string                 `json:"resolved,omitempty"`
+	Assignee        *string                `json:"assignee"`
+	Reporter        string                 `json:"reporter"`
+	Priority        string                 `json:"priority"`
+	Transitions     []Transition           `json:"transitions"`
+	Timeline        *Timeline              `json:"timeline,omitempty"`
+	CustomFields    map[string]interface{} `json:"custom_fields,omitempty"`
+	Labels          []string               `json:"labels,omitempty"`
+	Links           []string               `json:"links,omitempty"`
+	Classifications []string               `json:"classifications,omitempty"`
 }
 
 type Transition struct {
-	FromStatus     string `json:"from_status"`
-	ToStatus       string `json:"to_status"`
-	Author         string `json:"author"`
-	AuthorEmail    string `json:"author_user_name"`
-	TransitionTime string `json:"transition_time"`
+	FromStatus        string `json:"from_status"`
+	ToStatus          string `json:"to_status"`
+	Author            string `json:"author"`
+	AuthorEmail       string `json:"author_user_name"`
+	TransitionTime    string `json:"transition_time"`
+	TransitionTimeUTC string `json:"transition_time_utc,omitempty"`
 }
 
 // JiraClient wraps the JIRA client and provides methods for JIRA operations
 type JiraClient struct {
-	client *jira.Client
+	client       *jira.Client
+	customFields []string
 }
 
 // NewJiraClient creates a new JIRA client with authentication
 func NewJiraClient() (*JiraClient, error) {
+	return newJiraClientWithTransport(nil)
+}
+
+// newJiraClientWithTransport creates the JIRA client on top of transport; nil uses the default transport
+func newJiraClientWithTransport(transport http.RoundTripper) (*JiraClient, error) {
 	jira_token := os.Getenv("JIRA_API_TOKEN")
 	if jira_token == "" {
 		return nil, fmt.Errorf("JIRA token not found, set jira_token variable")
@@ -111,8 +168,9 @@ func NewJiraClient() (*JiraClient, error) {
 
 	// connect to JIRA
 	tp := jira.BasicAuthTransport{
-		Username: jira_username,
-		APIToken: jira_token,
+		Username:  jira_username,
+		APIToken:  jira_token,
+		Transport: transport,
 	}
 	client, err := jira.NewClient(jira_url, tp.Client())
 	if err != nil {
@@ -122,6 +180,24 @@ func NewJiraClient() (*JiraClient, error) {
 	return &JiraClient{client: client}, nil
 }
 
+// SetCustomFields selects the JIRA custom fields (e.g. customfield_10010) copied into each task
+func (jc *JiraClient) SetCustomFields(fields []string) {
+	jc.customFields = fields
+}
+
+// do sends a JIRA REST request relative to the JIRA URL, decoding the JSON response into v when it is not nil
+func (jc *JiraClient) do(ctx context.Context, method, path string, body, v interface{}) error {
+	req, err := jc.client.NewRequest(ctx, method, path, body)
+	if err != nil {
+		return fmt.Errorf("failed to create %s %s request: %v", method, path, err)
+	}
+	resp, err := jc.client.Do(req, v)
+	if err != nil {
+		return fmt.Errorf("%s %s failed: %v", method, path, jira.NewJiraError(resp, err))
+	}
+	return nil
+}
+
 func (jc *JiraClient) FetchJiraDetails(jiraIDs []string) TransitionCheckResponse {
 	// initialize the response
 	transitionCheckResponse := TransitionCheckResponse{}
@@ -133,60 +209,86 @@ func (jc *JiraClient) FetchJiraDetails(jiraIDs []string) TransitionCheckResponse
 		if issue == nil {
 			fmt.Fprintf(os.Stderr, "Got error for extracting issue with jira id: %s error %v\n", jiraId, err)
 			// Skip this ticket and continue with the next one
-			jiraTransitionResult := JiraTransitionResult{
-				Key:         jiraId,
-				Status:      "Error",
-				Description: "Error: Could not retrieve issue",
-				Type:        "Error",
-				Project:     "",
-				Created:     "",
-				Updated:     "",
-				Assignee:    nil,
-				Reporter:    "",
-				Priority:    "",
-				Transitions: []Transition{},
-			}
-			transitionCheckResponse.Tasks = append(transitionCheckResponse.Tasks, jiraTransitionResult)
+			transitionCheckResponse.Tasks = append(transitionCheckResponse.Tasks, errorTask(jiraId))
 			continue
 		}
 
 		// adding the jira result to the list of results
 		jiraTransitionResult := JiraTransitionResult{
-			Key:         issue.Key,
-			Status:      issue.Fields.Status.Name,
-			Description: getDescription(issue.Fields.Description),
-			Type:        issue.Fields.Type.Name,
-			Project:     issue.Fields.Project.Key,
-			Created:     getTimeAsString(issue.Fields.Created),
-			Updated:     getTimeAsString(issue.Fields.Updated),
-			Assignee:    getAssignee(issue.Fields.Assignee),
-			Reporter:    issue.Fields.Reporter.DisplayName,
-			Priority:    issue.Fields.Priority.Name,
-			Transitions: []Transition{},
+			Key:            issue.Key,
+			Summary:        issue.Fields.Summary,
+			Status:         issue.Fields.Status.Name,
+			StatusCategory: issue.Fields.Status.StatusCategory.Key,
+			Description:    getDescription(issue.Fields.Description),
+			Type:           issue.Fields.Type.Name,
+			Project:        issue.Fields.Project.Key,
+			Created:        getTimeAsString(issue.Fields.Created),
+			Updated:        getTimeAsString(issue.Fields.Updated),
+			Resolved:       getResolved(issue.Fields.Resolutiondate),
+			Assignee:       getAssignee(issue.Fields.Assignee),
+			Reporter:       issue.Fields.Reporter.DisplayName,
+			Priority:       issue.Fields.Priority.Name,
+			Transitions:    []Transition{},
 		}
 
-		if len(issue.Changelog.Histories) > 0 {
-			for _, history := range issue.Changelog.Histories {
+		if issue.Changelog != nil && len(issue.Changelog.Histories) > 0 {
+			for _, history := range sortHistories(issue.Changelog.Histories) {
 				for _, changelogItems := range history.Items {
 					if changelogItems.Field == "status" {
 						transition := Transition{
-							FromStatus:     changelogItems.FromString,
-							ToStatus:       changelogItems.ToString,
-							Author:         history.Author.DisplayName,
-							AuthorEmail:    history.Author.EmailAddress,
-							TransitionTime: history.Created,
+							FromStatus:        changelogItems.FromString,
+							ToStatus:          changelogItems.ToString,
+							Author:            history.Author.DisplayName,
+							AuthorEmail:       history.Author.EmailAddress,
+							TransitionTime:    history.Created,
+							TransitionTimeUTC: formatUTC(history.Created),
 						}
 						jiraTransitionResult.Transitions = append(jiraTransitionResult.Transitions, transition)
 					}
 				}
 			}
 		}
+		jiraTransitionResult.Labels = issue.Fields.Labels
+		for _, link := range issue.Fields.IssueLinks {
+			if link.OutwardIssue != nil {
+				jiraTransitionResult.Links = append(jiraTransitionResult.Links, link.OutwardIssue.Key)
+			}
+			if link.InwardIssue != nil {
+				jiraTransitionResult.Links = append(jiraTransitionResult.Links, link.InwardIssue.Key)
+			}
+		}
+
+		if len(jc.customFields) > 0 {
+			jiraTransitionResult.CustomFields = make(map[string]interface{})
+			for _, field := range jc.customFields {
+				jiraTransitionResult.CustomFields[field] = issue.Fields.Unknowns[field]
+			}
+		}
+
+		jiraTransitionResult.Timeline = buildTimeline(jiraTransitionResult)
 		transitionCheckResponse.Tasks = append(transitionCheckResponse.Tasks, jiraTransitionResult)
 	}
 
 	return transitionCheckResponse
 }
 
+// errorTask is the placeholder recorded for a ticket that could not be retrieved
+func errorTask(key string) JiraTransitionResult {
+	return JiraTransitionResult{
+		Key:         key,
+		Status:      "Error",
+		Description: "Error: Could not retrieve issue",
+		Type:        "Error",
+		Project:     "",
+		Created:     "",
+		Updated:     "",
+		Assignee:    nil,
+		Reporter:    "",
+		Priority:    "",
+		Transitions: []Transition{},
+	}
+}
+
 // Helper function to extract description text from JIRA description field
 func getDescription(desc interface{}) string {
 	if desc == nil {
@@ -236,6 +338,14 @@ func getAssignee(assignee *jira.User) *string {
 	return &assignee.DisplayName
 }
 
+// Helper function to get the resolution date as string, or "" if the issue is unresolved
+func getResolved(resolutionDate jira.Time) string {
+	if time.Time(resolutionDate).IsZero() {
+		return ""
+	}
+	return getTimeAsString(resolutionDate)
+}
+
 // Helper function to get time as string from JIRA time field
 func getTimeAsString(timeField interface{}) string {
 	if timeField == nil {
@@ -277,20 +387,11 @@ func formatWorkflow(transitions []Transition) string {
 		return "No transitions available"
 	}
 
-	// Sort transitions by time (newest first) and reverse to get chronological order
-	sortedTransitions := make([]Transition, len(transitions))
-	copy(sortedTransitions, transitions)
-	sort.Slice(sortedTransitions, func(i, j int) bool {
-		return sortedTransitions[i].TransitionTime > sortedTransitions[j].TransitionTime
-	})
-
 	// Extract status names in chronological order
 	statuses := make(map[string]bool)
 	var statusList []string
 
-	// Process transitions in reverse order to get chronological sequence
-	for i := len(sortedTransitions) - 1; i >= 0; i-- {
-		transition := sortedTransitions[i]
+	for _, transition := range sortTransitions(transitions) {
 		fromStatus := transition.FromStatus
 		toStatus := transition.ToStatus
 
@@ -324,7 +425,11 @@ func generateMarkdownContent(data TransitionCheckResponse) string {
 
 	// Header
 	content := "# Jira Tickets Summary\n"
-	content += fmt.Sprintf("Found %d associated tickets.\n\n", ticketCount)
+	content += fmt.Sprintf("Found %d associated tickets.\n", ticketCount)
+	if data.Summary != nil && data.Summary.SecurityTickets > 0 {
+		content += fmt.Sprintf("%d of them are security-sensitive.\n", data.Summary.SecurityTickets)
+	}
+	content += "\n"
 
 	// Table header
 	content += "| Key | Summary | Type | Priority | Workflow |\n"
@@ -363,6 +468,13 @@ func generateMarkdownContent(data TransitionCheckResponse) string {
 		content += fmt.Sprintf("| %s | %s | %s | %s | %s |\n", key, description, taskType, priority, workflow)
 	}
 
+	// Per-ticket timelines with time spent in each status
+	content += "\n" + generateTimelineMarkdown(tasks)
+
+	if data.Policy != nil {
+		content += "\n" + generatePolicyMarkdown(data.Policy)
+	}
+
 	return content
 }
 
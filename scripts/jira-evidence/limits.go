@@ -0,0 +1,175 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"unicode/utf8"
+)
+
+// minDescriptionBytes is the smallest description budget used when shrinking to fit the total budget
+const minDescriptionBytes = 256
+
+// minFieldBytes is the smallest per-field budget; the truncation marker alone takes up to 125 bytes
+const minFieldBytes = 128
+
+// LimitsConfig sets size budgets for the evidence; zero disables a limit
+type LimitsConfig struct {
+	MaxDescriptionBytes int `json:"max_description_bytes"`
+	MaxCustomFieldBytes int `json:"max_custom_field_bytes"`
+	MaxTotalBytes       int `json:"max_total_bytes"`
+}
+
+// LimitsInfo records in the evidence which limits applied and what was truncated
+type LimitsInfo struct {
+	MaxDescriptionBytes int      `json:"max_description_bytes,omitempty"`
+	MaxCustomFieldBytes int      `json:"max_custom_field_bytes,omitempty"`
+	MaxTotalBytes       int      `json:"max_total_bytes,omitempty"`
+	TruncatedFields     []string `json:"truncated_fields"`
+	TotalBytes          int      `json:"total_bytes"`
+}
+
+// validate checks the configured budgets
+func (c LimitsConfig) validate() error {
+	if c.MaxDescriptionBytes < 0 || c.MaxCustomFieldBytes < 0 || c.MaxTotalBytes < 0 {
+		return fmt.Errorf("size limits must not be negative")
+	}
+	if (c.MaxDescriptionBytes > 0 && c.MaxDescriptionBytes < minFieldBytes) ||
+		(c.MaxCustomFieldBytes > 0 && c.MaxCustomFieldBytes < minFieldBytes) {
+		return fmt.Errorf("max_description_bytes and max_custom_field_bytes must be at least %d, the size of the truncation marker", minFieldBytes)
+	}
+	return nil
+}
+
+// truncateText cuts text to at most maxBytes (on a UTF-8 boundary) and appends a marker with the
+// size and hash of the original, so the full value can still be matched against JIRA.
+// Budgets below minFieldBytes are raised to it so the marker always fits.
+func truncateText(text string, maxBytes int) (string, bool) {
+	if maxBytes <= 0 || len(text) <= maxBytes {
+		return text, false
+	}
+	if maxBytes < minFieldBytes {
+		maxBytes = minFieldBytes
+		if len(text) <= maxBytes {
+			return text, false
+		}
+	}
+
+	sum := sha256.Sum256([]byte(text))
+	marker := fmt.Sprintf(" … [truncated, original %d bytes, sha256:%s]", len(text), hex.EncodeToString(sum[:]))
+
+	cut := maxBytes - len(marker)
+	for cut > 0 && !utf8.RuneStart(text[cut]) {
+		cut--
+	}
+	return text[:cut] + marker, true
+}
+
+// ApplyLimits truncates descriptions and custom field values to their budgets and, when the whole
+// evidence is still above the total budget, keeps halving the description budget until it fits
+func ApplyLimits(data *TransitionCheckResponse, config LimitsConfig) {
+	if config.MaxDescriptionBytes == 0 && config.MaxCustomFieldBytes == 0 && config.MaxTotalBytes == 0 {
+		return
+	}
+
+	originals := make([]string, len(data.Tasks))
+	for i, task := range data.Tasks {
+		originals[i] = task.Description
+	}
+
+	info := &LimitsInfo{
+		MaxDescriptionBytes: config.MaxDescriptionBytes,
+		MaxCustomFieldBytes: config.MaxCustomFieldBytes,
+		MaxTotalBytes:       config.MaxTotalBytes,
+		TruncatedFields:     []string{},
+	}
+	truncated := make(map[string]bool)
+
+	// Custom field values are truncated once, on their JSON form
+	if config.MaxCustomFieldBytes > 0 {
+		for i := range data.Tasks {
+			task := &data.Tasks[i]
+			for field, value := range task.CustomFields {
+				jsonBytes, err := json.Marshal(value)
+				if err != nil || len(jsonBytes) <= config.MaxCustomFieldBytes {
+					continue
+				}
+				task.CustomFields[field], _ = truncateText(string(jsonBytes), config.MaxCustomFieldBytes)
+				truncated[task.Key+".custom_fields."+field] = true
+			}
+		}
+	}
+
+	truncateDescriptions := func(budget int) {
+		for i := range data.Tasks {
+			description, cut := truncateText(originals[i], budget)
+			data.Tasks[i].Description = description
+			if cut {
+				truncated[data.Tasks[i].Key+".description"] = true
+			}
+		}
+	}
+	truncateDescriptions(config.MaxDescriptionBytes)
+
+	size := evidenceSize(data)
+	if config.MaxTotalBytes > 0 && size > config.MaxTotalBytes {
+		budget := config.MaxDescriptionBytes
+		if budget == 0 {
+			budget = longestLength(originals)
+		}
+		for size > config.MaxTotalBytes && budget > minDescriptionBytes {
+			budget /= 2
+			if budget < minDescriptionBytes {
+				budget = minDescriptionBytes
+			}
+			truncateDescriptions(budget)
+			size = evidenceSize(data)
+		}
+		if size > config.MaxTotalBytes {
+			fmt.Fprintf(os.Stderr, "Warning: evidence is %d bytes, still above the %d byte budget after truncating descriptions\n", size, config.MaxTotalBytes)
+		}
+	}
+
+	for _, task := range data.Tasks {
+		if truncated[task.Key+".description"] {
+			info.TruncatedFields = append(info.TruncatedFields, task.Key+".description")
+		}
+		var fields []string
+		for field := range task.CustomFields {
+			if truncated[task.Key+".custom_fields."+field] {
+				fields = append(fields, task.Key+".custom_fields."+field)
+			}
+		}
+		sort.Strings(fields)
+		info.TruncatedFields = append(info.TruncatedFields, fields...)
+	}
+	if len(info.TruncatedFields) > 0 {
+		fmt.Fprintf(os.Stderr, "Warning: size limits hit, truncated %d field(s): %v\n", len(info.TruncatedFields), info.TruncatedFields)
+	}
+
+	data.Limits = info
+	info.TotalBytes = evidenceSize(data)
+}
+
+// evidenceSize returns the size of the evidence as written to the output file
+func evidenceSize(data *TransitionCheckResponse) int {
+	jsonBytes, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return 0
+	}
+	return len(jsonBytes)
+}
+
+// longestLength returns the length of the longest string
+func longestLength(values []string) int {
+	longest := 0
+	for _, value := range values {
+		if len(value) > longest {
+			longest = len(value)
+		}
+	}
+	return longest
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestTruncateText(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		maxBytes int
+		wantCut  bool
+	}{
+		{"disabled", strings.Repeat("a", 1000), 0, false},
+		{"within budget", "short", 128, false},
+		{"exactly at budget", strings.Repeat("a", 200), 200, false},
+		{"over budget", strings.Repeat("a", 1000), 200, true},
+		{"multi-byte runes", strings.Repeat("é", 500), 201, true},
+		{"budget below the marker", strings.Repeat("a", 1000), 10, true},
+		{"small text below the raised budget", strings.Repeat("a", 100), 10, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, cut := truncateText(tt.text, tt.maxBytes)
+			if cut != tt.wantCut {
+				t.Fatalf("truncateText cut = %v, want %v", cut, tt.wantCut)
+			}
+			if !cut {
+				if got != tt.text {
+					t.Errorf("truncateText changed text that fits")
+				}
+				return
+			}
+			budget := tt.maxBytes
+			if budget < minFieldBytes {
+				budget = minFieldBytes
+			}
+			if len(got) > budget {
+				t.Errorf("truncateText returned %d bytes, budget %d", len(got), budget)
+			}
+			if !utf8.ValidString(got) {
+				t.Errorf("truncateText returned invalid UTF-8")
+			}
+			if !strings.Contains(got, "[truncated, original ") || !strings.HasPrefix(tt.text, strings.SplitN(got, " … ", 2)[0]) {
+				t.Errorf("truncateText = %q, want a prefix of the original and the marker", got)
+			}
+		})
+	}
+}
+
+func TestLimitsConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  LimitsConfig
+		wantErr bool
+	}{
+		{"unset", LimitsConfig{}, false},
+		{"valid", LimitsConfig{MaxDescriptionBytes: 4096, MaxCustomFieldBytes: 2048, MaxTotalBytes: 100000}, false},
+		{"minimum", LimitsConfig{MaxDescriptionBytes: minFieldBytes, MaxCustomFieldBytes: minFieldBytes}, false},
+		{"negative", LimitsConfig{MaxTotalBytes: -1}, true},
+		{"description below the marker", LimitsConfig{MaxDescriptionBytes: 50}, true},
+		{"custom field below the marker", LimitsConfig{MaxCustomFieldBytes: 100}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.config.validate(); (err != nil) != tt.wantErr {
+				t.Errorf("validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestApplyLimits(t *testing.T) {
+	data := TransitionCheckResponse{Tasks: []JiraTransitionResult{
+		{Key: "EV-1", Description: strings.Repeat("a", 5000), CustomFields: map[string]interface{}{"customfield_1": strings.Repeat("b", 1000)}},
+		{Key: "EV-2", Description: "short"},
+	}}
+
+	ApplyLimits(&data, LimitsConfig{MaxDescriptionBytes: 1000, MaxCustomFieldBytes: 500})
+
+	if len(data.Tasks[0].Description) > 1000 {
+		t.Errorf("description is %d bytes, want at most 1000", len(data.Tasks[0].Description))
+	}
+	if data.Tasks[1].Description != "short" {
+		t.Errorf("short description changed to %q", data.Tasks[1].Description)
+	}
+	if value, _ := data.Tasks[0].CustomFields["customfield_1"].(string); len(value) > 500 {
+		t.Errorf("custom field is %d bytes, want at most 500", len(value))
+	}
+	want := []string{"EV-1.description", "EV-1.custom_fields.customfield_1"}
+	if data.Limits == nil || strings.Join(data.Limits.TruncatedFields, ",") != strings.Join(want, ",") {
+		t.Errorf("limits = %+v, want truncated fields %v", data.Limits, want)
+	}
+}
+
+func TestApplyLimitsTotalBudget(t *testing.T) {
+	data := TransitionCheckResponse{Tasks: []JiraTransitionResult{
+		{Key: "EV-1", Description: strings.Repeat("a", 20000)},
+		{Key: "EV-2", Description: strings.Repeat("b", 20000)},
+	}}
+
+	ApplyLimits(&data, LimitsConfig{MaxTotalBytes: 8000})
+
+	if data.Limits.TotalBytes > 8000 {
+		t.Errorf("evidence is %d bytes, want at most 8000", data.Limits.TotalBytes)
+	}
+	if len(data.Limits.TruncatedFields) != 2 {
+		t.Errorf("truncated fields = %v, want both descriptions", data.Limits.TruncatedFields)
+	}
+}
@@ -0,0 +1,220 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// linearIssueQuery fetches an issue by its identifier (ENG-123) together with the first page of its history
+const linearIssueQuery = `query Issue($id: String!) {
+  issue(id: $id) {
+    title description createdAt updatedAt completedAt canceledAt priorityLabel
+    state { name type }
+    team { key }
+    assignee { name email }
+    creator { name email }
+    labels { nodes { name } }
+    history(first: 100) { pageInfo { hasNextPage endCursor } nodes { createdAt actor { name email } fromState { name } toState { name } } }
+  }
+}`
+
+// linearHistoryQuery fetches a further page of an issue's history
+const linearHistoryQuery = `query History($id: String!, $after: String) {
+  issue(id: $id) {
+    history(first: 100, after: $after) { pageInfo { hasNextPage endCursor } nodes { createdAt actor { name email } fromState { name } toState { name } } }
+  }
+}`
+
+// LinearClient reads issues from the Linear GraphQL API
+type LinearClient struct {
+	restClient
+}
+
+// linearUser is the subset of a Linear user used here
+type linearUser struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+// linearHistory is one page of an issue's history
+type linearHistory struct {
+	PageInfo struct {
+		HasNextPage bool   `json:"hasNextPage"`
+		EndCursor   string `json:"endCursor"`
+	} `json:"pageInfo"`
+	Nodes []struct {
+		CreatedAt string      `json:"createdAt"`
+		Actor     *linearUser `json:"actor"`
+		FromState *struct {
+			Name string `json:"name"`
+		} `json:"fromState"`
+		ToState *struct {
+			Name string `json:"name"`
+		} `json:"toState"`
+	} `json:"nodes"`
+}
+
+// linearIssue is the subset of a Linear issue used here
+type linearIssue struct {
+	Title         string `json:"title"`
+	Description   string `json:"description"`
+	CreatedAt     string `json:"createdAt"`
+	UpdatedAt     string `json:"updatedAt"`
+	CompletedAt   string `json:"completedAt"`
+	CanceledAt    string `json:"canceledAt"`
+	PriorityLabel string `json:"priorityLabel"`
+	State         struct {
+		Name string `json:"name"`
+		Type string `json:"type"`
+	} `json:"state"`
+	Team struct {
+		Key string `json:"key"`
+	} `json:"team"`
+	Assignee *linearUser `json:"assignee"`
+	Creator  *linearUser `json:"creator"`
+	Labels   struct {
+		Nodes []struct {
+			Name string `json:"name"`
+		} `json:"nodes"`
+	} `json:"labels"`
+	History linearHistory `json:"history"`
+}
+
+// NewLinearClient creates a Linear client from LINEAR_API_KEY and LINEAR_API_URL
+func NewLinearClient() (*LinearClient, error) {
+	apiKey := os.Getenv("LINEAR_API_KEY")
+	if apiKey == "" {
+		return nil, fmt.Errorf("Linear API key not found, set LINEAR_API_KEY variable")
+	}
+	apiURL := os.Getenv("LINEAR_API_URL")
+	if apiURL == "" {
+		apiURL = "https://api.linear.app/graphql"
+	}
+
+	// API keys are sent as they are; OAuth tokens would need a Bearer prefix
+	return &LinearClient{restClient: newRESTClient(apiURL, map[string]string{"Authorization": apiKey})}, nil
+}
+
+// query runs a GraphQL query and decodes its data into v
+func (lc *LinearClient) query(query string, variables map[string]interface{}, v interface{}) error {
+	var result struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	request := map[string]interface{}{"query": query, "variables": variables}
+	if err := lc.do(http.MethodPost, "", request, &result); err != nil {
+		return fmt.Errorf("GraphQL request failed: %v", err)
+	}
+	if len(result.Errors) > 0 {
+		var messages []string
+		for _, e := range result.Errors {
+			messages = append(messages, e.Message)
+		}
+		return fmt.Errorf("GraphQL error: %s", strings.Join(messages, "; "))
+	}
+	if err := json.Unmarshal(result.Data, v); err != nil {
+		return fmt.Errorf("failed to decode GraphQL data: %v", err)
+	}
+	return nil
+}
+
+// linearStatusCategory maps a Linear workflow state type to a status category
+func linearStatusCategory(stateType string) string {
+	switch stateType {
+	case "completed", "canceled":
+		return "done"
+	case "started":
+		return "indeterminate"
+	default:
+		return "new"
+	}
+}
+
+// FetchDetails implements IssueTracker for Linear. Keys are issue identifiers such as ENG-123;
+// workflow state changes in the issue history become transitions.
+func (lc *LinearClient) FetchDetails(ids []string) TransitionCheckResponse {
+	return fetchEach(ids, lc.fetchIssue)
+}
+
+// fetchIssue retrieves one issue and its complete history
+func (lc *LinearClient) fetchIssue(id string) (JiraTransitionResult, error) {
+	var data struct {
+		Issue *linearIssue `json:"issue"`
+	}
+	if err := lc.query(linearIssueQuery, map[string]interface{}{"id": id}, &data); err != nil {
+		return JiraTransitionResult{}, err
+	}
+	if data.Issue == nil {
+		return JiraTransitionResult{}, fmt.Errorf("issue %s not found", id)
+	}
+	issue := data.Issue
+
+	history := issue.History
+	nodes := history.Nodes
+	for history.PageInfo.HasNextPage {
+		var page struct {
+			Issue struct {
+				History linearHistory `json:"history"`
+			} `json:"issue"`
+		}
+		variables := map[string]interface{}{"id": id, "after": history.PageInfo.EndCursor}
+		if err := lc.query(linearHistoryQuery, variables, &page); err != nil {
+			return JiraTransitionResult{}, err
+		}
+		history = page.Issue.History
+		nodes = append(nodes, history.Nodes...)
+	}
+
+	task := JiraTransitionResult{
+		Key:            id,
+		Summary:        issue.Title,
+		Status:         issue.State.Name,
+		StatusCategory: linearStatusCategory(issue.State.Type),
+		Description:    issue.Description,
+		Type:           "Issue",
+		Project:        issue.Team.Key,
+		Created:        issue.CreatedAt,
+		Updated:        issue.UpdatedAt,
+		Resolved:       issue.CompletedAt,
+		Priority:       issue.PriorityLabel,
+		Transitions:    []Transition{},
+	}
+	if task.Resolved == "" {
+		task.Resolved = issue.CanceledAt
+	}
+	if issue.Assignee != nil {
+		task.Assignee = &issue.Assignee.Name
+	}
+	if issue.Creator != nil {
+		task.Reporter = issue.Creator.Name
+	}
+	for _, label := range issue.Labels.Nodes {
+		task.Labels = append(task.Labels, label.Name)
+	}
+
+	for _, node := range nodes {
+		if node.FromState == nil || node.ToState == nil {
+			continue
+		}
+		transition := Transition{
+			FromStatus:        node.FromState.Name,
+			ToStatus:          node.ToState.Name,
+			TransitionTime:    node.CreatedAt,
+			TransitionTimeUTC: formatUTC(node.CreatedAt),
+		}
+		if node.Actor != nil {
+			transition.Author = node.Actor.Name
+			transition.AuthorEmail = node.Actor.Email
+		}
+		task.Transitions = append(task.Transitions, transition)
+	}
+	task.Transitions = sortTransitions(task.Transitions)
+	task.Timeline = buildTimeline(task)
+
+	return task, nil
+}
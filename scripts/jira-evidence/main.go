@@ -8,6 +8,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 )
 
@@ -123,7 +124,38 @@ func extractJiraIDs(startCommit, jiraIDRegex, currentJiraID string) ([]string, e
 	return result, nil
 }
 
+// collectCommits returns the commits in startCommit..HEAD together with the JIRA IDs each one references
+func collectCommits(startCommit, jiraIDRegex string) ([]Commit, error) {
+	// Fields are separated by unit separators and records by record separators so subjects can contain anything
+	cmd := exec.Command("git", "log", "--pretty=format:%H%x1f%an%x1f%ae%x1f%aI%x1f%s%x1e", startCommit+"..HEAD")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commits: %v", err)
+	}
+
+	regex, err := regexp.Compile(jiraIDRegex)
+	if err != nil {
+		return nil, fmt.Errorf("invalid JIRA ID regex: %v", err)
+	}
+
+	var commits []Commit
+	for _, record := range strings.Split(string(output), "\x1e") {
+		fields := strings.Split(strings.TrimSpace(record), "\x1f")
+		if len(fields) != 5 {
+			continue
+		}
+		commits = append(commits, Commit{
+			Hash:        fields[0],
+			Author:      fields[1],
+			AuthorEmail: fields[2],
+			Date:        fields[3],
+			Subject:     fields[4],
+			JiraIDs:     regex.FindAllString(fields[4], -1),
+		})
+	}
 
+	return commits, nil
+}
 
 // checkGitRepository checks if we're in a git repository
 func checkGitRepository() error {
@@ -154,12 +186,53 @@ func displayUsage() {
 	fmt.Println("Usage:")
 	fmt.Println("  ./main [OPTIONS] <start_commit>")
 	fmt.Println("  ./main <jira_id1> [jira_id2] [jira_id3] ...")
+	fmt.Println("  ./main annotate [OPTIONS]")
+	fmt.Println("  ./main fix-version [OPTIONS]")
+	fmt.Println("  ./main transition --to STATUS [OPTIONS]")
+	fmt.Println("  ./main remote-link [OPTIONS]")
+	fmt.Println("  ./main attach --key KEY [OPTIONS]")
+	fmt.Println("")
+	fmt.Println("Subcommands:")
+	fmt.Println("  annotate               Comment build and package coordinates on every ticket of an evidence file")
+	fmt.Println("                         (--evidence, --build-number, --image-name, --image-version, --docker-repo,")
+	fmt.Println("                         --run-url, --evidence-url, --artifactory-url; see './main annotate -h')")
+	fmt.Println("  fix-version            Ensure a JIRA release version for the build and add it to every ticket's fixVersions")
+	fmt.Println("                         (same options plus --version-template, --release, --release-date)")
+	fmt.Println("  transition             Move every ticket of an evidence file to a target status after deployment")
+	fmt.Println("                         (--evidence, --to, --past-statuses, --results)")
+	fmt.Println("  remote-link            Link every ticket of an evidence file to the package in Artifactory and the CI run")
+	fmt.Println("                         (same options as annotate; --artifactory-url is required)")
+	fmt.Println("  attach                 Sign an evidence file (DSSE) and upload it to the Artifactory evidence API")
+	fmt.Println("                         (--key, --key-alias, --predicate-type, --markdown, --subject package|build|release-bundle,")
+	fmt.Println("                         --build-name, --release-bundle, --release-bundle-version, --project, --subject-repo-path, --package-type)")
+	fmt.Println("  The JIRA subcommands accept --dry-run (run JIRA reads, record writes) and --dry-run-output FILE,")
+	fmt.Println("  before or after the subcommand name (./main --dry-run annotate or ./main annotate --dry-run)")
 	fmt.Println("")
 	fmt.Println("Options:")
-	fmt.Println("  -r, --regex PATTERN    JIRA ID regex pattern (default: '[A-Z]+-[0-9]+')")
-	fmt.Println("  -o, --output FILE      Output file for JIRA data (default: transformed_jira_data.json)")
+	fmt.Println("  -r, --regex PATTERN    JIRA ID regex pattern (default: '[A-Z]+-[0-9]+', or the --tracker default)")
+	fmt.Println("  -o, --output FILE      Output file for the JSON evidence (default: transformed_jira_data.json)")
 	fmt.Println("  --extract-only         Only extract JIRA IDs, don't fetch details")
 	fmt.Println("  --extract-from-git     Extract JIRA IDs from git commits (legacy mode)")
+	fmt.Println("  --format FORMAT        Output format: json, csv or jsonl (default: json); csv/jsonl are written next to the JSON evidence")
+	fmt.Println("  --export-file FILE     File for csv/jsonl output (default: output file with a .csv/.jsonl extension)")
+	fmt.Println("  --rows ROWS            Rows for csv/jsonl: tickets or transitions (default: tickets)")
+	fmt.Println("  --columns COLS         Comma-separated csv/jsonl columns, e.g. key,status,customfield_10010")
+	fmt.Println("  --custom-fields IDS    Comma-separated JIRA custom fields to include in the output")
+	fmt.Println("  --require-status LIST  Fail unless every ticket is in one of these statuses, e.g. 'Done,Ready for Release'")
+	fmt.Println("  --require-status-category LIST  Also accept tickets in these status categories, e.g. 'done'")
+	fmt.Println("  --sod-transitions LIST Flag tickets where a commit author made one of these transitions, e.g. 'In Review->Approved'")
+	fmt.Println("  --sod-enforce          Fail the run on segregation-of-duties findings (default: warn only)")
+	fmt.Println("  --check-commit-dates   Flag tickets created after, or resolved before, a referencing commit")
+	fmt.Println("  --stale-days N         Flag tickets not updated for more than N days")
+	fmt.Println("  --freshness-enforce    Fail the run on freshness findings (default: warn only)")
+	fmt.Println("  --config FILE          JSON configuration file (custom CEL rules, required workflows, security, redaction, size limits, tracker routing)")
+	fmt.Println("  --fail-on LIST         Conditions that exit non-zero: git, no-tickets, partial-fetch, total-fetch, policy or none")
+	fmt.Println("                         (default: total-fetch,policy)")
+	fmt.Println("  --tracker NAME         Issue tracker: jira, github, gitlab, linear or youtrack (default: jira)")
+	fmt.Println("  --strict               Fail when any requested ticket cannot be retrieved (same as adding partial-fetch)")
+	fmt.Println("  --max-errors N         Fail when more than N tickets cannot be retrieved")
+	fmt.Println("  --pull-requests        Map commits to GitHub pull requests, extract ticket keys from their titles, bodies and")
+	fmt.Println("                         head branches, and record reviewers and approval state (git mode only)")
 	fmt.Println("  -h, --help             Display this help message")
 	fmt.Println("")
 	fmt.Println("Arguments:")
@@ -170,27 +243,113 @@ func displayUsage() {
 	fmt.Println("  JIRA_URL              JIRA instance URL")
 	fmt.Println("  JIRA_USERNAME         JIRA username")
 	fmt.Println("  JIRA_ID_REGEX         JIRA ID regex pattern (can be overridden with -r)")
+	fmt.Println("  ISSUE_TRACKER         Issue tracker: jira, github, gitlab, linear or youtrack (can be overridden with --tracker)")
+	fmt.Println("  GITHUB_TOKEN, GITHUB_REPOSITORY, GITHUB_API_URL  GitHub credentials, repository and API URL for the github tracker and --pull-requests")
+	fmt.Println("  JIRA_PULL_REQUESTS    Enable pull request enrichment (true/false)")
+	fmt.Println("  GITLAB_TOKEN, GITLAB_PROJECT, GITLAB_API_URL  GitLab Issues tracker credentials, default project and API URL")
+	fmt.Println("  LINEAR_API_KEY, LINEAR_API_URL  Linear tracker API key and GraphQL endpoint")
+	fmt.Println("  YOUTRACK_URL, YOUTRACK_TOKEN  YouTrack tracker URL and token")
 	fmt.Println("  OUTPUT_FILE           Output file path (can be overridden with -o)")
+	fmt.Println("  EXPORT_FILE           csv/jsonl export file path (can be overridden with --export-file)")
+	fmt.Println("  JIRA_REQUIRED_STATUSES  Required final statuses (can be overridden with --require-status)")
+	fmt.Println("  JIRA_REQUIRED_STATUS_CATEGORIES  Required status categories (can be overridden with --require-status-category)")
+	fmt.Println("  JIRA_SOD_TRANSITIONS  Segregation-of-duties approval transitions (can be overridden with --sod-transitions)")
+	fmt.Println("  JIRA_SOD_ENFORCE      Fail on segregation-of-duties findings (true/false)")
+	fmt.Println("  JIRA_CHECK_COMMIT_DATES  Enable commit date checks (true/false)")
+	fmt.Println("  JIRA_STALE_DAYS       Stale ticket threshold in days (can be overridden with --stale-days)")
+	fmt.Println("  JIRA_FRESHNESS_ENFORCE  Fail on freshness findings (true/false)")
+	fmt.Println("  JIRA_EVIDENCE_CONFIG  Configuration file path (can be overridden with --config)")
+	fmt.Println("  JIRA_REDACTION_SALT   Salt for hashed redaction")
+	fmt.Println("  JIRA_FAIL_ON          Fatal conditions (can be overridden with --fail-on)")
+	fmt.Println("  JIRA_STRICT           Fail when any ticket cannot be retrieved (true/false)")
+	fmt.Println("  JIRA_MAX_ERRORS       Maximum number of tickets that may fail to be retrieved (can be overridden with --max-errors)")
+	fmt.Println("  JIRA_CUSTOM_FIELDS    Comma-separated JIRA custom fields (can be overridden with --custom-fields)")
+	fmt.Println("  JIRA_DONE_STATUSES    Comma-separated statuses treated as resolved (default: Done,Closed,Resolved,Released)")
 	fmt.Println("  ATTACH_OPTIONAL_CUSTOM_MARKDOWN_TO_EVIDENCE  Generate markdown report (true/false)")
+	fmt.Println("  ATTACH_OPTIONAL_CUSTOM_HTML_TO_EVIDENCE      Generate HTML report (true/false)")
+	fmt.Println("  GITHUB_ACTIONS        When 'true', write $GITHUB_STEP_SUMMARY, annotations and $GITHUB_OUTPUT")
+	fmt.Println("  BUILD_NUMBER, IMAGE_NAME, IMAGE_VERSION, DOCKER_REPO, EVIDENCE_URL  Build and package coordinates for the subcommands")
+	fmt.Println("  ARTIFACTORY_URL, JF_URL  Artifactory base URL for the subcommands (can be overridden with --artifactory-url)")
+	fmt.Println("  JIRA_DRY_RUN          Record instead of sending JIRA writes in every subcommand (true/false)")
+	fmt.Println("  JIRA_DRY_RUN_OUTPUT   File for the recorded requests (can be overridden with --dry-run-output)")
+	fmt.Println("  JIRA_VERSION_TEMPLATE Version name template for fix-version (can be overridden with --version-template)")
+	fmt.Println("  BUILD_DATE            Release date for fix-version --release (YYYY-MM-DD)")
+	fmt.Println("  JIRA_TARGET_STATUS, JIRA_PAST_STATUSES, JIRA_TRANSITION_RESULTS  Defaults for transition --to, --past-statuses, --results")
+	fmt.Println("  JF_ACCESS_TOKEN, ARTIFACTORY_ACCESS_TOKEN  Artifactory access token for attach")
+	fmt.Println("  EVIDENCE_PRIVATE_KEY, PRIVATE_KEY, EVIDENCE_KEY_ALIAS  Signing key and key alias for attach")
+	fmt.Println("  EVIDENCE_SUBJECT, PREDICATE_TYPE, JF_PROJECT, PACKAGE_TYPE  Defaults for attach --subject, --predicate-type, --project, --package-type")
+	fmt.Println("  BUILD_NAME, JFROG_CLI_BUILD_NAME, RELEASE_BUNDLE_NAME, RELEASE_BUNDLE_VERSION  Build and release bundle subjects for attach")
+	fmt.Println("")
+	fmt.Println("Exit Codes:")
+	fmt.Println("  0  Success, or a condition not listed in --fail-on")
+	fmt.Println("  1  Internal error (JIRA client creation, writing output)")
+	fmt.Println("  2  Usage error (invalid flags, arguments or configuration)")
+	fmt.Println("  3  Git error (not a repository, HEAD or start commit missing)")
+	fmt.Println("  4  No tickets found in the commit range")
+	fmt.Println("  5  Partial fetch failure (some tickets could not be retrieved, see --strict and --max-errors)")
+	fmt.Println("  6  Total fetch failure (no ticket could be retrieved)")
+	fmt.Println("  7  Policy violation")
 	fmt.Println("")
 	fmt.Println("Examples:")
 	fmt.Println("  ./main abc123def456")
 	fmt.Println("  ./main -r 'EV-\\d+' -o jira_results.json abc123def456")
 	fmt.Println("  ./main --extract-only abc123def456")
+	fmt.Println("  ./main --format csv --rows transitions abc123def456")
 	fmt.Println("  ./main EV-123 EV-456 EV-789")
+	fmt.Println("  ./main --tracker github abc123def456")
+	fmt.Println("  ./main --pull-requests abc123def456")
+	fmt.Println("  ./main --tracker gitlab '#123' platform/infra#45")
+	fmt.Println("  ./main annotate --evidence jira-evidence.json --image-name app --build-number 42 --docker-repo app-docker-dev")
+	fmt.Println("  ./main transition --to Released --past-statuses Closed --evidence jira-evidence.json")
+	fmt.Println("  ./main fix-version --release --version-template '{{.Project}} {{.BuildNumber}}' --image-name app --build-number 42 --docker-repo app-docker-dev")
+	fmt.Println("  ./main attach --key signing.pem --key-alias SIGNING-KEY --image-name app --build-number 42 --docker-repo app-docker-dev")
 }
 
 
 
 func main() {
+	// Dispatch subcommands, which parse their own flags
+	if args := hoistDryRunFlags(os.Args[1:]); len(args) > 0 {
+		switch args[0] {
+		case "annotate":
+			os.Exit(runAnnotate(args[1:]))
+		case "fix-version":
+			os.Exit(runFixVersion(args[1:]))
+		case "transition":
+			os.Exit(runTransition(args[1:]))
+		case "remote-link":
+			os.Exit(runRemoteLink(args[1:]))
+		case "attach":
+			os.Exit(runAttach(args[1:]))
+		}
+	}
+
 	// Parse command line flags
 	var (
-		jiraIDRegex = flag.String("r", "", "JIRA ID regex pattern")
-		outputFile  = flag.String("o", "", "Output file for JIRA data")
-		extractOnly = flag.Bool("extract-only", false, "Only extract JIRA IDs, don't fetch details")
-		extractFromGit = flag.Bool("extract-from-git", false, "Extract JIRA IDs from git commits (legacy mode)")
-		help        = flag.Bool("h", false, "Display help message")
-		helpLong    = flag.Bool("help", false, "Display help message")
+		jiraIDRegex           = flag.String("r", "", "JIRA ID regex pattern")
+		outputFile            = flag.String("o", "", "Output file for JIRA data")
+		extractOnly           = flag.Bool("extract-only", false, "Only extract JIRA IDs, don't fetch details")
+		extractFromGit        = flag.Bool("extract-from-git", false, "Extract JIRA IDs from git commits (legacy mode)")
+		format                = flag.String("format", "json", "Output format: json, csv or jsonl")
+		rows                  = flag.String("rows", "tickets", "Rows for csv/jsonl output: tickets or transitions")
+		columns               = flag.String("columns", "", "Comma-separated columns for csv/jsonl output")
+		exportFile            = flag.String("export-file", "", "File for csv/jsonl output (default: output file with the format's extension)")
+		customFields          = flag.String("custom-fields", "", "Comma-separated JIRA custom fields to include")
+		requireStatus         = flag.String("require-status", "", "Comma-separated statuses every ticket must be in")
+		requireStatusCategory = flag.String("require-status-category", "", "Comma-separated status categories every ticket must be in")
+		sodTransitions        = flag.String("sod-transitions", "", "Comma-separated approval transitions (FROM->TO) that commit authors must not make")
+		sodEnforce            = flag.Bool("sod-enforce", false, "Fail the run on segregation-of-duties findings")
+		checkCommitDates      = flag.Bool("check-commit-dates", false, "Flag tickets created after or resolved before a referencing commit")
+		staleDays             = flag.Int("stale-days", 0, "Flag tickets not updated for more than N days (0 disables)")
+		freshnessEnforce      = flag.Bool("freshness-enforce", false, "Fail the run on ticket freshness findings")
+		configFile            = flag.String("config", "", "JSON configuration file (policy rules, required workflows, security, redaction, size limits)")
+		failOnFlag            = flag.String("fail-on", "", "Comma-separated conditions that exit non-zero: git, no-tickets, partial-fetch, total-fetch, policy or none")
+		strict                = flag.Bool("strict", false, "Fail the run when any requested ticket cannot be retrieved")
+		maxErrors             = flag.Int("max-errors", -1, "Fail the run when more than N tickets cannot be retrieved (-1 disables)")
+		trackerName           = flag.String("tracker", "", "Issue tracker: jira, github, gitlab, linear or youtrack (default: jira)")
+		pullRequestsFlag      = flag.Bool("pull-requests", false, "Map commits to their GitHub pull requests and extract ticket keys from them")
+		help                  = flag.Bool("h", false, "Display help message")
+		helpLong              = flag.Bool("help", false, "Display help message")
 	)
 	flag.Parse()
 
@@ -200,22 +359,129 @@ func main() {
 		return
 	}
 
+	// Resolve which conditions are fatal
+	if *failOnFlag == "" {
+		*failOnFlag = os.Getenv("JIRA_FAIL_ON")
+		if *failOnFlag == "" {
+			*failOnFlag = defaultFailOn
+		}
+	}
+	failOn, err := parseFailOn(*failOnFlag)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(exitUsage)
+	}
+	if *strict || os.Getenv("JIRA_STRICT") == "true" {
+		failOn[failOnPartialFetch] = true
+	}
+	// -1 is both the default and an explicit "disabled", so only an unset flag falls back to the environment
+	maxErrorsSet := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == "max-errors" {
+			maxErrorsSet = true
+		}
+	})
+	if !maxErrorsSet && os.Getenv("JIRA_MAX_ERRORS") != "" {
+		if *maxErrors, err = strconv.Atoi(os.Getenv("JIRA_MAX_ERRORS")); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: invalid JIRA_MAX_ERRORS: %v\n", err)
+			os.Exit(exitUsage)
+		}
+	}
+
+	// Resolve the issue tracker
+	if *trackerName == "" {
+		*trackerName = os.Getenv("ISSUE_TRACKER")
+		if *trackerName == "" {
+			*trackerName = "jira"
+		}
+	}
+	tracker, err := lookupTracker(*trackerName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(exitUsage)
+	}
+
+	// Resolve export options
+	exportOpts := ExportOptions{Format: *format, Rows: *rows, Columns: parseColumns(*columns)}
+	if err := validateExportOptions(exportOpts); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(exitUsage)
+	}
+	if *customFields == "" {
+		*customFields = os.Getenv("JIRA_CUSTOM_FIELDS")
+	}
+	jiraCustomFields := append(parseColumns(*customFields), customFieldColumns(exportOpts.Columns)...)
+
+	// Resolve policy options
+	if *requireStatus == "" {
+		*requireStatus = os.Getenv("JIRA_REQUIRED_STATUSES")
+	}
+	if *requireStatusCategory == "" {
+		*requireStatusCategory = os.Getenv("JIRA_REQUIRED_STATUS_CATEGORIES")
+	}
+	if *sodTransitions == "" {
+		*sodTransitions = os.Getenv("JIRA_SOD_TRANSITIONS")
+	}
+	approvalTransitions, err := parseApprovalTransitions(*sodTransitions)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(exitUsage)
+	}
+	if *configFile == "" {
+		*configFile = os.Getenv("JIRA_EVIDENCE_CONFIG")
+	}
+	config, err := loadConfig(*configFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(exitUsage)
+	}
+	tracker = routeTrackers(*trackerName, tracker, config.Trackers)
+	rules, err := compileRules(config.Rules)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: invalid policy rule: %v\n", err)
+		os.Exit(exitUsage)
+	}
+	if *staleDays == 0 && os.Getenv("JIRA_STALE_DAYS") != "" {
+		if *staleDays, err = strconv.Atoi(os.Getenv("JIRA_STALE_DAYS")); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: invalid JIRA_STALE_DAYS: %v\n", err)
+			os.Exit(exitUsage)
+		}
+	}
+	policyOpts := PolicyOptions{
+		RequiredStatuses:           parseColumns(*requireStatus),
+		RequiredStatusCategories:   parseColumns(*requireStatusCategory),
+		ApprovalTransitions:        approvalTransitions,
+		EnforceSegregationOfDuties: *sodEnforce || os.Getenv("JIRA_SOD_ENFORCE") == "true",
+		RequiredWorkflows:          config.RequiredWorkflows,
+		Security:                   config.Security,
+		Freshness: FreshnessOptions{
+			CheckCommitDates: *checkCommitDates || os.Getenv("JIRA_CHECK_COMMIT_DATES") == "true",
+			StaleDays:        *staleDays,
+			Enforce:          *freshnessEnforce || os.Getenv("JIRA_FRESHNESS_ENFORCE") == "true",
+		},
+		Rules: rules,
+	}
+
 	// Handle legacy extract-from-git mode
 	if *extractFromGit {
 		args := flag.Args()
 		if len(args) < 2 {
 			fmt.Println("Usage: ./main --extract-from-git <start_commit> <jira_id_regex>")
-			os.Exit(1)
+			os.Exit(exitUsage)
 		}
 
 		startCommit := args[0]
 		regex := args[1]
+		if _, err := regexp.Compile(regex); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: invalid JIRA ID regex: %v\n", err)
+			os.Exit(exitUsage)
+		}
 
 		// Get branch info
 		branchName, commitHash, currentJiraID, err := getBranchInfo()
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error getting branch info: %v\n", err)
-			os.Exit(1)
+			os.Exit(exitGitError)
 		}
 
 		fmt.Printf("BRANCH_NAME: %s\n", branchName)
@@ -225,25 +491,25 @@ func main() {
 		// Validate HEAD
 		if err := validateHEAD(); err != nil {
 			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
-			os.Exit(0) // Exit gracefully as per original behavior
+			exitFor(failOn, failOnGit, exitGitError) // Exits gracefully unless git errors are fatal
 		}
 
 		// Validate commit
 		if err := validateCommit(startCommit); err != nil {
 			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
-			os.Exit(0) // Exit gracefully as per original behavior
+			exitFor(failOn, failOnGit, exitGitError) // Exits gracefully unless git errors are fatal
 		}
 
 		// Extract JIRA IDs
 		jiraIDs, err := extractJiraIDs(startCommit, regex, currentJiraID)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error extracting JIRA IDs: %v\n", err)
-			os.Exit(1)
+			os.Exit(exitGitError)
 		}
 
 		if len(jiraIDs) == 0 {
 			fmt.Println("No JIRA IDs found")
-			os.Exit(0)
+			exitFor(failOn, failOnNoTickets, exitNoTickets)
 		}
 
 		// Print comma-separated JIRA IDs
@@ -258,7 +524,7 @@ func main() {
 	if len(args) == 0 {
 		fmt.Println("Error: start_commit is required")
 		displayUsage()
-		os.Exit(1)
+		os.Exit(exitUsage)
 	}
 
 	startCommit := args[0]
@@ -266,14 +532,18 @@ func main() {
 	// Check if we have arguments for direct JIRA ID processing (only if not in extract-only mode)
 	if !*extractOnly && len(args) > 0 && !strings.HasPrefix(args[0], "-") {
 		// Check if the argument matches the JIRA ID pattern
-		pattern := "[A-Z]+-[0-9]+"
+		pattern := tracker.DefaultIDRegex
 		if *jiraIDRegex != "" {
 			pattern = *jiraIDRegex
 		}
 		regex, err := regexp.Compile(pattern)
-		if err == nil && regex.MatchString(args[0]) {
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: invalid JIRA ID regex: %v\n", err)
+			os.Exit(exitUsage)
+		}
+		if regex.MatchString(args[0]) {
 			// Direct JIRA ID processing mode
-			processJiraIDs(args)
+			processJiraIDs(normalizeIDs(tracker, args), tracker, exportOpts, jiraCustomFields, policyOpts, config, failOn, *maxErrors)
 			return
 		}
 		// If it doesn't match the pattern, treat it as a start commit
@@ -283,9 +553,13 @@ func main() {
 	if *jiraIDRegex == "" {
 		*jiraIDRegex = os.Getenv("JIRA_ID_REGEX")
 		if *jiraIDRegex == "" {
-			*jiraIDRegex = "[A-Z]+-[0-9]+"
+			*jiraIDRegex = tracker.DefaultIDRegex
 		}
 	}
+	if _, err := regexp.Compile(*jiraIDRegex); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: invalid JIRA ID regex: %v\n", err)
+		os.Exit(exitUsage)
+	}
 
 	if *outputFile == "" {
 		*outputFile = os.Getenv("OUTPUT_FILE")
@@ -293,17 +567,38 @@ func main() {
 			*outputFile = "transformed_jira_data.json"
 		}
 	}
+	// The output file always holds the JSON evidence; csv/jsonl go to a separate export file
+	if err := validateOutputFile(*outputFile, "json"); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(exitUsage)
+	}
+	if exportOpts.Format != "json" {
+		if *exportFile == "" {
+			*exportFile = os.Getenv("EXPORT_FILE")
+			if *exportFile == "" {
+				*exportFile = exportFileName(*outputFile, exportOpts.Format)
+			}
+		}
+		if err := validateOutputFile(*exportFile, exportOpts.Format); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(exitUsage)
+		}
+	}
 
 	// Check if we're in a git repository
 	if err := checkGitRepository(); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+		os.Exit(exitGitError)
 	}
 
 	fmt.Println("=== JIRA Details Fetching Process ===")
 	fmt.Printf("Start Commit: %s\n", startCommit)
+	fmt.Printf("Issue Tracker: %s\n", strings.ToLower(*trackerName))
 	fmt.Printf("JIRA ID Regex: %s\n", *jiraIDRegex)
 	fmt.Printf("Output File: %s\n", *outputFile)
+	if exportOpts.Format != "json" {
+		fmt.Printf("Export File: %s (%s)\n", *exportFile, exportOpts.Format)
+	}
 	fmt.Println("")
 
 	// Step 1: Extract JIRA IDs from git commits
@@ -313,7 +608,7 @@ func main() {
 	branchName, commitHash, currentJiraID, err := getBranchInfo()
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error getting branch info: %v\n", err)
-		os.Exit(1)
+		os.Exit(exitGitError)
 	}
 
 	// Display branch information
@@ -323,25 +618,62 @@ func main() {
 	// Validate HEAD
 	if err := validateHEAD(); err != nil {
 		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
-		os.Exit(0) // Exit gracefully as per original behavior
+		exitFor(failOn, failOnGit, exitGitError) // Exits gracefully unless git errors are fatal
 	}
 
 	// Validate commit
 	if err := validateCommit(startCommit); err != nil {
 		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
-		os.Exit(0) // Exit gracefully as per original behavior
+		exitFor(failOn, failOnGit, exitGitError) // Exits gracefully unless git errors are fatal
 	}
 
 	// Extract JIRA IDs
 	jiraIDs, err := extractJiraIDs(startCommit, *jiraIDRegex, currentJiraID)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error extracting JIRA IDs: %v\n", err)
-		os.Exit(1)
+		os.Exit(exitGitError)
+	}
+	jiraIDs = normalizeIDs(tracker, jiraIDs)
+
+	// Collect commit attribution for the evidence
+	commits, err := collectCommits(startCommit, *jiraIDRegex)
+	if err != nil {
+		if !*extractOnly {
+			fmt.Fprintf(os.Stderr, "Error collecting commits: %v\n", err)
+			os.Exit(exitGitError)
+		}
+		// Extract-only mode prints the keys and never needed commit attribution
+		fmt.Fprintf(os.Stderr, "Warning: could not collect commits: %v\n", err)
+	}
+	for i := range commits {
+		commits[i].JiraIDs = normalizeIDs(tracker, commits[i].JiraIDs)
+	}
+
+	// Map commits to pull requests, which often carry the only ticket reference
+	var pullRequests []PullRequest
+	var pullRequestErrors []string
+	if *pullRequestsFlag || os.Getenv("JIRA_PULL_REQUESTS") == "true" {
+		githubClient, err := NewGitHubClient()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error creating GitHub client for --pull-requests: %v\n", err)
+			os.Exit(exitError)
+		}
+		pullRequests, pullRequestErrors, err = githubClient.CollectPullRequests(commits, *jiraIDRegex)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error collecting pull requests: %v\n", err)
+			os.Exit(exitError)
+		}
+		for i := range pullRequests {
+			pullRequests[i].JiraIDs = normalizeIDs(tracker, pullRequests[i].JiraIDs)
+			jiraIDs = appendMissing(jiraIDs, pullRequests[i].JiraIDs)
+		}
+		fmt.Printf("Pull Requests: %s\n", pullRequestSummary(pullRequests))
 	}
 
 	if len(jiraIDs) == 0 {
 		fmt.Println("No JIRA IDs found in commit range")
-		os.Exit(0)
+		ReportToGitHubActions(TransitionCheckResponse{}, "", tracker)
+		exitFor(failOn, failOnNoTickets, exitNoTickets)
 	}
 
 	fmt.Printf("Found JIRA IDs: %s\n", strings.Join(jiraIDs, ", "))
@@ -356,33 +688,65 @@ func main() {
 	fmt.Println("")
 	fmt.Println("Step 2: Fetching JIRA details...")
 
-	// Create JIRA client and process JIRA IDs
-	jiraClient, err := NewJiraClient()
+	// Create the issue tracker client and process JIRA IDs
+	issueTracker, err := tracker.New(jiraCustomFields)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error creating JIRA client: %v\n", err)
-		os.Exit(1)
+		fmt.Fprintf(os.Stderr, "Error creating %s client: %v\n", *trackerName, err)
+		os.Exit(exitError)
 	}
 
 	// Process JIRA IDs and get results
-	response := jiraClient.FetchJiraDetails(jiraIDs)
+	response := issueTracker.FetchDetails(jiraIDs)
+	dropPullRequests(&response)
+	response.Commits = commits
+	response.PullRequests = pullRequests
+	response.PullRequestErrors = pullRequestErrors
+
+	// Tag security-sensitive tickets before policies see them
+	if err := ClassifySecurityTickets(&response, policyOpts.Security); err != nil {
+		fmt.Fprintf(os.Stderr, "Error classifying tickets: %v\n", err)
+		os.Exit(exitError)
+	}
+
+	// Evaluate policies so their results are part of the written evidence
+	EvaluatePolicies(&response, policyOpts)
+
+	// Redact personal data before anything is written
+	ApplyRedaction(&response, config.Redaction)
+
+	// Keep the evidence within the configured size budgets
+	ApplyLimits(&response, config.Limits)
 
 	// Step 3: Write results to file
 	fmt.Println("")
 	fmt.Println("Step 3: Writing results...")
 
-	jsonBytes, err := json.MarshalIndent(response, "", "  ")
+	outputBytes, err := json.MarshalIndent(response, "", "  ")
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error marshaling JSON: %v\n", err)
-		os.Exit(1)
+		os.Exit(exitError)
 	}
 
-	if err := writeToFile(*outputFile, jsonBytes); err != nil {
+	if err := writeToFile(*outputFile, outputBytes); err != nil {
 		fmt.Fprintf(os.Stderr, "Error writing to file: %v\n", err)
-		os.Exit(1)
+		os.Exit(exitError)
 	}
 
 	fmt.Printf("JIRA data saved to: %s\n", *outputFile)
 
+	if exportOpts.Format != "json" {
+		exportBytes, err := RenderOutput(response, exportOpts)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error rendering %s output: %v\n", exportOpts.Format, err)
+			os.Exit(exitError)
+		}
+		if err := writeToFile(*exportFile, exportBytes); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing to file: %v\n", err)
+			os.Exit(exitError)
+		}
+		fmt.Printf("%s export saved to: %s\n", strings.ToUpper(exportOpts.Format), *exportFile)
+	}
+
 	// Step 4: Generate markdown report if requested
 	attachMarkdown := os.Getenv("ATTACH_OPTIONAL_CUSTOM_MARKDOWN_TO_EVIDENCE")
 	if attachMarkdown == "true" {
@@ -394,31 +758,68 @@ func main() {
 		fmt.Println("Step 4: Skipping markdown report generation (ATTACH_OPTIONAL_CUSTOM_MARKDOWN_TO_EVIDENCE != 'true')")
 	}
 
+	// Step 5: Generate HTML report if requested
+	attachHTML := os.Getenv("ATTACH_OPTIONAL_CUSTOM_HTML_TO_EVIDENCE")
+	if attachHTML == "true" {
+		if err := GenerateHTMLReport(response, *outputFile); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: Failed to generate HTML report: %v\n", err)
+			// Don't exit on HTML generation failure
+		}
+	} else {
+		fmt.Println("Step 5: Skipping HTML report generation (ATTACH_OPTIONAL_CUSTOM_HTML_TO_EVIDENCE != 'true')")
+	}
+
+	// Step 6: Publish summary, annotations and outputs when running in GitHub Actions
+	ReportToGitHubActions(response, *outputFile, tracker)
+
+	if code, message := resultExitCode(response, failOn, *maxErrors, tracker.DisplayName); code != exitSuccess {
+		fmt.Fprintf(os.Stderr, "❌ %s\n", message)
+		os.Exit(code)
+	}
+
 	fmt.Println("")
 	fmt.Println("=== Process completed successfully ===")
 }
 
 // processJiraIDs handles direct JIRA ID processing (original functionality)
-func processJiraIDs(jiraIDs []string) {
-	// Create a new Jira client
-	jiraClient, err := NewJiraClient()
+func processJiraIDs(jiraIDs []string, tracker trackerBackend, exportOpts ExportOptions, customFields []string, policyOpts PolicyOptions, config Config, failOn FailOn, maxErrors int) {
+	// Create a new issue tracker client
+	issueTracker, err := tracker.New(customFields)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error creating JIRA client: %v\n", err)
-		os.Exit(1)
+		fmt.Fprintf(os.Stderr, "Error creating issue tracker client: %v\n", err)
+		os.Exit(exitError)
 	}
 
 	// Get response
-	response := jiraClient.FetchJiraDetails(jiraIDs)
-
-	// marshal the response to JSON
-	jsonBytes, err := json.Marshal(response)
+	response := issueTracker.FetchDetails(jiraIDs)
+	dropPullRequests(&response)
+	if err := ClassifySecurityTickets(&response, policyOpts.Security); err != nil {
+		fmt.Fprintf(os.Stderr, "Error classifying tickets: %v\n", err)
+		os.Exit(exitError)
+	}
+	EvaluatePolicies(&response, policyOpts)
+	ApplyRedaction(&response, config.Redaction)
+	ApplyLimits(&response, config.Limits)
+
+	// marshal the response in the requested format (compact for JSON, as before)
+	var outputBytes []byte
+	if exportOpts.Format == "json" {
+		outputBytes, err = json.Marshal(response)
+	} else {
+		outputBytes, err = RenderOutput(response, exportOpts)
+	}
 	if err != nil {
-		fmt.Println("Error marshaling JSON", err)
-		os.Exit(1)
+		fmt.Println("Error marshaling output", err)
+		os.Exit(exitError)
 	}
 
 	// return response to caller through stdout
-	os.Stdout.Write(jsonBytes)
+	os.Stdout.Write(outputBytes)
+
+	if code, message := resultExitCode(response, failOn, maxErrors, tracker.DisplayName); code != exitSuccess {
+		fmt.Fprintf(os.Stderr, "❌ %s\n", message)
+		os.Exit(code)
+	}
 }
 
 
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// PolicyOptions configures the policy checks evaluated over the fetched tickets
+type PolicyOptions struct {
+	RequiredStatuses         []string // tickets must be in one of these statuses
+	RequiredStatusCategories []string // or in one of these status category keys (new, indeterminate, done)
+
+	ApprovalTransitions        []ApprovalTransition // transitions that must not be made by a commit author
+	EnforceSegregationOfDuties bool                 // fail the run on segregation-of-duties findings
+
+	RequiredWorkflows map[string]WorkflowRequirement // required intermediate statuses per issue type
+
+	Freshness FreshnessOptions // date checks between tickets and referencing commits
+
+	Security SecurityConfig // security ticket classification and review requirement
+
+	Rules []compiledRule // custom CEL rules from the config file
+}
+
+// requiredStatusPolicy is the policy name recorded for the required status check
+const requiredStatusPolicy = "required-status"
+
+// Policy result severities; failed warnings are reported without failing the run
+const (
+	severityError   = "error"
+	severityWarning = "warning"
+)
+
+// containsFold reports whether values contains value, ignoring case and surrounding whitespace
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(strings.TrimSpace(v), strings.TrimSpace(value)) {
+			return true
+		}
+	}
+	return false
+}
+
+// addPolicyResults records policy results on the response and recomputes the overall verdict
+func addPolicyResults(data *TransitionCheckResponse, results ...PolicyResult) {
+	if data.Policy == nil {
+		data.Policy = &PolicyReport{Passed: true, Results: []PolicyResult{}}
+	}
+	for _, result := range results {
+		data.Policy.Results = append(data.Policy.Results, result)
+		if !result.Passed && result.Severity != severityWarning {
+			data.Policy.Passed = false
+		}
+	}
+}
+
+// checkRequiredStatus verifies that every ticket reached an allowed final status or status category
+func checkRequiredStatus(tasks []JiraTransitionResult, opts PolicyOptions) []PolicyResult {
+	allowed := strings.Join(append(append([]string{}, opts.RequiredStatuses...), opts.RequiredStatusCategories...), ", ")
+
+	var results []PolicyResult
+	for _, task := range tasks {
+		result := PolicyResult{Policy: requiredStatusPolicy, Key: task.Key, Severity: severityError}
+
+		switch {
+		case task.Type == "Error":
+			result.Message = "ticket could not be retrieved, status cannot be verified"
+		case containsFold(opts.RequiredStatuses, task.Status):
+			result.Passed = true
+			result.Message = fmt.Sprintf("status %q is allowed", task.Status)
+		case task.StatusCategory != "" && containsFold(opts.RequiredStatusCategories, task.StatusCategory):
+			result.Passed = true
+			result.Message = fmt.Sprintf("status %q is in allowed category %q", task.Status, task.StatusCategory)
+		default:
+			result.Message = fmt.Sprintf("status %q is not one of: %s", task.Status, allowed)
+		}
+
+		results = append(results, result)
+	}
+	return results
+}
+
+// EvaluatePolicies runs the configured policy checks and records the results on the response
+func EvaluatePolicies(data *TransitionCheckResponse, opts PolicyOptions) {
+	if len(opts.RequiredStatuses) > 0 || len(opts.RequiredStatusCategories) > 0 {
+		addPolicyResults(data, checkRequiredStatus(data.Tasks, opts)...)
+	}
+	if len(opts.ApprovalTransitions) > 0 {
+		addPolicyResults(data, checkSegregationOfDuties(*data, opts.ApprovalTransitions, opts.EnforceSegregationOfDuties)...)
+	}
+	if len(opts.RequiredWorkflows) > 0 {
+		addPolicyResults(data, checkRequiredWorkflow(data.Tasks, opts.RequiredWorkflows)...)
+	}
+
+	if opts.Freshness.CheckCommitDates || opts.Freshness.StaleDays > 0 {
+		addPolicyResults(data, checkFreshness(*data, opts.Freshness, time.Now())...)
+	}
+
+	if len(opts.Security.ReviewStatuses) > 0 {
+		addPolicyResults(data, checkSecurityReview(data.Tasks, opts.Security)...)
+	}
+
+	// Custom rules run last so they can also inspect the results of the built-in checks
+	if len(opts.Rules) > 0 {
+		addPolicyResults(data, evaluateRules(*data, opts.Rules)...)
+	}
+}
+
+// generatePolicyMarkdown renders the policy results as a markdown section
+func generatePolicyMarkdown(policy *PolicyReport) string {
+	verdict := "PASS"
+	if !policy.Passed {
+		verdict = "FAIL"
+	}
+
+	content := fmt.Sprintf("## Policy Results: %s\n\n", verdict)
+	content += "| Policy | Ticket | Result | Message |\n"
+	content += "|--------|--------|--------|---------|\n"
+	for _, result := range policy.Results {
+		outcome := "✅ PASS"
+		if !result.Passed {
+			outcome = "❌ FAIL"
+			if result.Severity == severityWarning {
+				outcome = "⚠️ WARN"
+			}
+		}
+		content += fmt.Sprintf("| %s | %s | %s | %s |\n",
+			escapeMarkdown(result.Policy), escapeMarkdown(result.Key), outcome, escapeMarkdown(result.Message))
+	}
+
+	return content
+}
@@ -0,0 +1,78 @@
+package main
+
+import "testing"
+
+func TestCheckRequiredStatus(t *testing.T) {
+	opts := PolicyOptions{RequiredStatuses: []string{"Done", "Ready for Release"}, RequiredStatusCategories: []string{"done"}}
+
+	unretrieved := storyTicket("EV-4", "")
+	unretrieved.Type = "Error"
+	resolved := storyTicket("EV-3", "Verified")
+	resolved.StatusCategory = "done"
+	inProgress := storyTicket("EV-5", "In Progress")
+	inProgress.StatusCategory = "indeterminate"
+
+	tests := []struct {
+		name       string
+		task       JiraTransitionResult
+		wantPassed bool
+	}{
+		{"allowed status", storyTicket("EV-1", "Done"), true},
+		{"allowed status, other case and spacing", storyTicket("EV-2", " ready for release"), true},
+		{"allowed status category", resolved, true},
+		{"status not allowed", inProgress, false},
+		{"no status category", storyTicket("EV-6", "In Review"), false},
+		{"ticket not retrieved", unretrieved, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := checkRequiredStatus([]JiraTransitionResult{tt.task}, opts)
+			if len(results) != 1 {
+				t.Fatalf("got %d results, want 1", len(results))
+			}
+			if results[0].Passed != tt.wantPassed || results[0].Key != tt.task.Key || results[0].Policy != requiredStatusPolicy {
+				t.Errorf("result = %+v, want passed %v for %s", results[0], tt.wantPassed, tt.task.Key)
+			}
+			if results[0].Severity != severityError {
+				t.Errorf("severity = %q, want %q", results[0].Severity, severityError)
+			}
+		})
+	}
+}
+
+func TestEvaluatePoliciesRequiredStatus(t *testing.T) {
+	data := TransitionCheckResponse{Tasks: []JiraTransitionResult{storyTicket("EV-1", "Done"), storyTicket("EV-2", "In Progress")}}
+
+	EvaluatePolicies(&data, PolicyOptions{RequiredStatuses: []string{"Done"}})
+
+	if data.Policy == nil || data.Policy.Passed || len(data.Policy.Results) != 2 {
+		t.Fatalf("policy = %+v, want two results and a failed verdict", data.Policy)
+	}
+
+	data = TransitionCheckResponse{Tasks: []JiraTransitionResult{storyTicket("EV-1", "Done")}}
+	EvaluatePolicies(&data, PolicyOptions{})
+	if data.Policy != nil {
+		t.Errorf("policy = %+v, want none without configured checks", data.Policy)
+	}
+}
+
+func TestAddPolicyResultsSeverity(t *testing.T) {
+	tests := []struct {
+		name    string
+		results []PolicyResult
+		want    bool
+	}{
+		{"all passed", []PolicyResult{{Passed: true, Severity: severityError}, {Passed: true, Severity: severityWarning}}, true},
+		{"failed warning", []PolicyResult{{Passed: true, Severity: severityError}, {Passed: false, Severity: severityWarning}}, true},
+		{"failed error", []PolicyResult{{Passed: false, Severity: severityError}, {Passed: true, Severity: severityWarning}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var data TransitionCheckResponse
+			addPolicyResults(&data, tt.results...)
+			if data.Policy.Passed != tt.want || len(data.Policy.Results) != len(tt.results) {
+				t.Errorf("policy = %+v, want passed %v", data.Policy, tt.want)
+			}
+		})
+	}
+}
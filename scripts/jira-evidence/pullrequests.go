@@ -0,0 +1,221 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Approval states recorded for a pull request
+const (
+	approvalApproved         = "approved"          // at least one approval and no outstanding change request
+	approvalChangesRequested = "changes_requested" // a reviewer's latest verdict requests changes
+	approvalNotReviewed      = "not_reviewed"      // no approval or change request, possibly only comments
+	approvalUnknown          = "unknown"           // the reviews could not be read
+)
+
+// PullRequest is a pull request containing commits of the evidence range, with its review state
+// and the ticket keys referenced in its title, body and head branch
+type PullRequest struct {
+	Number        int      `json:"number"`
+	Title         string   `json:"title"`
+	URL           string   `json:"url"`
+	HeadBranch    string   `json:"head_branch"`
+	BaseBranch    string   `json:"base_branch"`
+	Author        string   `json:"author"`
+	State         string   `json:"state"`
+	MergedAt      string   `json:"merged_at,omitempty"`
+	Reviewers     []string `json:"reviewers"`
+	Approvers     []string `json:"approvers"`
+	ApprovalState string   `json:"approval_state"`
+	Commits       []string `json:"commits"`
+	JiraIDs       []string `json:"jira_ids"`
+}
+
+// githubPull is the subset of a GitHub pull request used here
+type githubPull struct {
+	Number             int          `json:"number"`
+	Title              string       `json:"title"`
+	Body               string       `json:"body"`
+	HTMLURL            string       `json:"html_url"`
+	State              string       `json:"state"`
+	MergedAt           string       `json:"merged_at"`
+	User               githubUser   `json:"user"`
+	RequestedReviewers []githubUser `json:"requested_reviewers"`
+	Head               struct {
+		Ref string `json:"ref"`
+	} `json:"head"`
+	Base struct {
+		Ref string `json:"ref"`
+	} `json:"base"`
+}
+
+// githubReview is the subset of a GitHub pull request review used here
+type githubReview struct {
+	User  githubUser `json:"user"`
+	State string     `json:"state"`
+}
+
+// commitPulls returns the pull requests that contain a commit
+func (gc *GitHubClient) commitPulls(hash string) ([]githubPull, error) {
+	var pulls []githubPull
+	if err := gc.get(fmt.Sprintf("/repos/%s/commits/%s/pulls", gc.repository, hash), &pulls); err != nil {
+		return nil, err
+	}
+	return pulls, nil
+}
+
+// pullReviews returns every review of a pull request, following pagination
+func (gc *GitHubClient) pullReviews(number int) ([]githubReview, error) {
+	return fetchPages(func(page int) ([]githubReview, error) {
+		var batch []githubReview
+		path := fmt.Sprintf("/repos/%s/pulls/%d/reviews?per_page=%d&page=%d", gc.repository, number, pageSize, page)
+		return batch, gc.get(path, &batch)
+	})
+}
+
+// reviewSummary returns the reviewers, the approvers and the approval state of a pull request.
+// Each reviewer's latest approval, change request or dismissal counts; comments do not change a verdict.
+func reviewSummary(pull githubPull, reviews []githubReview) ([]string, []string, string) {
+	reviewers := make(map[string]bool)
+	for _, reviewer := range pull.RequestedReviewers {
+		reviewers[reviewer.Login] = true
+	}
+	verdicts := make(map[string]string)
+	for _, review := range reviews {
+		// Reviews of deleted accounts have no login and cannot be attributed
+		if review.User.Login == "" {
+			continue
+		}
+		reviewers[review.User.Login] = true
+		switch review.State {
+		case "APPROVED", "CHANGES_REQUESTED", "DISMISSED":
+			verdicts[review.User.Login] = review.State
+		}
+	}
+
+	var approvers []string
+	changesRequested := false
+	for login, verdict := range verdicts {
+		switch verdict {
+		case "APPROVED":
+			approvers = append(approvers, login)
+		case "CHANGES_REQUESTED":
+			changesRequested = true
+		}
+	}
+	sort.Strings(approvers)
+
+	names := make([]string, 0, len(reviewers))
+	for login := range reviewers {
+		if login != "" {
+			names = append(names, login)
+		}
+	}
+	sort.Strings(names)
+
+	switch {
+	case changesRequested:
+		return names, approvers, approvalChangesRequested
+	case len(approvers) > 0:
+		return names, approvers, approvalApproved
+	default:
+		return names, approvers, approvalNotReviewed
+	}
+}
+
+// CollectPullRequests maps every commit to the pull requests containing it and extracts the ticket keys
+// referenced in their titles, bodies and head branches. Lookups that fail are reported and returned as
+// messages, since the pull request data is incomplete without them. A pull request whose reviews cannot
+// be read gets the unknown approval state and no reviewers or approvers.
+func (gc *GitHubClient) CollectPullRequests(commits []Commit, jiraIDRegex string) ([]PullRequest, []string, error) {
+	regex, err := regexp.Compile(jiraIDRegex)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid JIRA ID regex: %v", err)
+	}
+
+	var failures []string
+	fail := func(message string) {
+		fmt.Fprintf(os.Stderr, "⚠️  %s\n", message)
+		failures = append(failures, message)
+	}
+
+	var pullRequests []*PullRequest
+	byNumber := make(map[int]*PullRequest)
+	for _, commit := range commits {
+		pulls, err := gc.commitPulls(commit.Hash)
+		if err != nil {
+			fail(fmt.Sprintf("Could not look up pull requests for commit %s: %v", commit.Hash, err))
+			continue
+		}
+		for _, pull := range pulls {
+			if pr, ok := byNumber[pull.Number]; ok {
+				pr.Commits = append(pr.Commits, commit.Hash)
+				continue
+			}
+
+			reviewers, approvers, approvalState := []string{}, []string{}, approvalUnknown
+			if reviews, err := gc.pullReviews(pull.Number); err != nil {
+				fail(fmt.Sprintf("Could not read reviews of pull request #%d: %v", pull.Number, err))
+			} else {
+				reviewers, approvers, approvalState = reviewSummary(pull, reviews)
+			}
+
+			pr := &PullRequest{
+				Number:        pull.Number,
+				Title:         pull.Title,
+				URL:           pull.HTMLURL,
+				HeadBranch:    pull.Head.Ref,
+				BaseBranch:    pull.Base.Ref,
+				Author:        pull.User.Login,
+				State:         pull.State,
+				MergedAt:      pull.MergedAt,
+				Reviewers:     reviewers,
+				Approvers:     approvers,
+				ApprovalState: approvalState,
+				Commits:       []string{commit.Hash},
+				JiraIDs:       appendMissing(nil, regex.FindAllString(pull.Title+"\n"+pull.Body+"\n"+pull.Head.Ref, -1)),
+			}
+			if pr.MergedAt != "" {
+				pr.State = "merged"
+			}
+			byNumber[pull.Number] = pr
+			pullRequests = append(pullRequests, pr)
+		}
+	}
+
+	result := make([]PullRequest, 0, len(pullRequests))
+	for _, pr := range pullRequests {
+		result = append(result, *pr)
+	}
+	return result, failures, nil
+}
+
+// appendMissing appends the ids not yet in list, keeping their order
+func appendMissing(list []string, ids []string) []string {
+	seen := make(map[string]bool, len(list))
+	for _, id := range list {
+		seen[id] = true
+	}
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			list = append(list, id)
+		}
+	}
+	if list == nil {
+		list = []string{}
+	}
+	return list
+}
+
+// pullRequestSummary describes the pull requests for console output
+func pullRequestSummary(pullRequests []PullRequest) string {
+	var parts []string
+	for _, pr := range pullRequests {
+		parts = append(parts, fmt.Sprintf("#%d (%s)", pr.Number, strings.ReplaceAll(pr.ApprovalState, "_", " ")))
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,217 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// reviewBy returns a pull request review by login in state
+func reviewBy(login, state string) githubReview {
+	return githubReview{User: githubUser{Login: login}, State: state}
+}
+
+func TestReviewSummary(t *testing.T) {
+	tests := []struct {
+		name          string
+		requested     []string
+		reviews       []githubReview
+		wantReviewers string
+		wantApprovers string
+		wantState     string
+	}{
+		{
+			name:      "no reviews",
+			wantState: approvalNotReviewed,
+		},
+		{
+			name:          "approved",
+			reviews:       []githubReview{reviewBy("alice", "APPROVED")},
+			wantReviewers: "alice",
+			wantApprovers: "alice",
+			wantState:     approvalApproved,
+		},
+		{
+			name:          "latest verdict wins over an earlier change request",
+			reviews:       []githubReview{reviewBy("alice", "CHANGES_REQUESTED"), reviewBy("alice", "APPROVED")},
+			wantReviewers: "alice",
+			wantApprovers: "alice",
+			wantState:     approvalApproved,
+		},
+		{
+			name:          "latest verdict wins over an earlier approval",
+			reviews:       []githubReview{reviewBy("alice", "APPROVED"), reviewBy("alice", "CHANGES_REQUESTED")},
+			wantReviewers: "alice",
+			wantState:     approvalChangesRequested,
+		},
+		{
+			name:          "dismissal clears an approval",
+			reviews:       []githubReview{reviewBy("alice", "APPROVED"), reviewBy("alice", "DISMISSED")},
+			wantReviewers: "alice",
+			wantState:     approvalNotReviewed,
+		},
+		{
+			name:          "comment does not change a verdict",
+			reviews:       []githubReview{reviewBy("alice", "APPROVED"), reviewBy("alice", "COMMENTED")},
+			wantReviewers: "alice",
+			wantApprovers: "alice",
+			wantState:     approvalApproved,
+		},
+		{
+			name:          "comments only",
+			reviews:       []githubReview{reviewBy("alice", "COMMENTED"), reviewBy("bob", "COMMENTED")},
+			wantReviewers: "alice,bob",
+			wantState:     approvalNotReviewed,
+		},
+		{
+			name:          "one change request beats other approvals",
+			reviews:       []githubReview{reviewBy("carol", "APPROVED"), reviewBy("bob", "CHANGES_REQUESTED"), reviewBy("alice", "APPROVED")},
+			wantReviewers: "alice,bob,carol",
+			wantApprovers: "alice,carol",
+			wantState:     approvalChangesRequested,
+		},
+		{
+			name:          "requested reviewers who never reviewed",
+			requested:     []string{"dave", "alice"},
+			reviews:       []githubReview{reviewBy("alice", "APPROVED")},
+			wantReviewers: "alice,dave",
+			wantApprovers: "alice",
+			wantState:     approvalApproved,
+		},
+		{
+			name:          "empty logins are dropped",
+			requested:     []string{""},
+			reviews:       []githubReview{reviewBy("", "COMMENTED"), reviewBy("alice", "COMMENTED")},
+			wantReviewers: "alice",
+			wantState:     approvalNotReviewed,
+		},
+		{
+			name:      "approval without a login does not count",
+			reviews:   []githubReview{reviewBy("", "APPROVED")},
+			wantState: approvalNotReviewed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var pull githubPull
+			for _, login := range tt.requested {
+				pull.RequestedReviewers = append(pull.RequestedReviewers, githubUser{Login: login})
+			}
+			reviewers, approvers, state := reviewSummary(pull, tt.reviews)
+			if strings.Join(reviewers, ",") != tt.wantReviewers {
+				t.Errorf("reviewers = %v, want %s", reviewers, tt.wantReviewers)
+			}
+			if strings.Join(approvers, ",") != tt.wantApprovers {
+				t.Errorf("approvers = %v, want %s", approvers, tt.wantApprovers)
+			}
+			if state != tt.wantState {
+				t.Errorf("approval state = %s, want %s", state, tt.wantState)
+			}
+		})
+	}
+}
+
+// newPullRequestServer serves the commit pull request and review endpoints of the GitHub API for acme/app
+func newPullRequestServer(t *testing.T, pullsByCommit map[string][]githubPull, reviews map[string][]githubReview) *GitHubClient {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body interface{}
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/repos/acme/app/commits/"):
+			hash := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/repos/acme/app/commits/"), "/pulls")
+			pulls, ok := pullsByCommit[hash]
+			if !ok {
+				http.Error(w, "not found", http.StatusNotFound)
+				return
+			}
+			body = pulls
+		case strings.HasPrefix(r.URL.Path, "/repos/acme/app/pulls/"):
+			number := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/repos/acme/app/pulls/"), "/reviews")
+			pullReviews, ok := reviews[number]
+			if !ok {
+				http.Error(w, "server error", http.StatusInternalServerError)
+				return
+			}
+			body = pullReviews
+		default:
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(body)
+	}))
+	t.Cleanup(server.Close)
+
+	t.Setenv("GITHUB_TOKEN", "token")
+	t.Setenv("GITHUB_REPOSITORY", "acme/app")
+	t.Setenv("GITHUB_API_URL", server.URL)
+	client, err := NewGitHubClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+func TestCollectPullRequests(t *testing.T) {
+	pull := githubPull{Number: 7, Title: "EV-1: add export", Body: "Also fixes EV-2 and EV-1", MergedAt: "2024-01-03T10:00:00Z", State: "closed"}
+	pull.Head.Ref = "feature/EV-3-export"
+	pull.Base.Ref = "main"
+	pull.User.Login = "alice"
+	client := newPullRequestServer(t,
+		map[string][]githubPull{"aaa": {pull}, "bbb": {pull}, "ccc": {}},
+		map[string][]githubReview{"7": {reviewBy("bob", "APPROVED")}},
+	)
+
+	pullRequests, failures, err := client.CollectPullRequests([]Commit{{Hash: "aaa"}, {Hash: "bbb"}, {Hash: "ccc"}}, "[A-Z]+-[0-9]+")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(failures) != 0 {
+		t.Errorf("failures = %v, want none", failures)
+	}
+
+	if len(pullRequests) != 1 {
+		t.Fatalf("got %d pull requests, want 1", len(pullRequests))
+	}
+	pr := pullRequests[0]
+	if pr.Number != 7 || pr.State != "merged" || pr.Author != "alice" || pr.HeadBranch != "feature/EV-3-export" {
+		t.Errorf("pull request = %+v, want merged #7 by alice", pr)
+	}
+	if strings.Join(pr.Commits, ",") != "aaa,bbb" {
+		t.Errorf("commits = %v, want aaa,bbb", pr.Commits)
+	}
+	if strings.Join(pr.JiraIDs, ",") != "EV-1,EV-2,EV-3" {
+		t.Errorf("ticket keys = %v, want EV-1,EV-2,EV-3", pr.JiraIDs)
+	}
+	if pr.ApprovalState != approvalApproved || strings.Join(pr.Approvers, ",") != "bob" {
+		t.Errorf("approval = %s by %v, want approved by bob", pr.ApprovalState, pr.Approvers)
+	}
+}
+
+func TestCollectPullRequestsIncomplete(t *testing.T) {
+	// Reviews of #8 and the pull requests of commit bbb cannot be read
+	pull := githubPull{Number: 8, Title: "EV-4: fix export", State: "open"}
+	pull.RequestedReviewers = []githubUser{{Login: "bob"}}
+	client := newPullRequestServer(t, map[string][]githubPull{"aaa": {pull}}, map[string][]githubReview{})
+
+	pullRequests, failures, err := client.CollectPullRequests([]Commit{{Hash: "aaa"}, {Hash: "bbb"}}, "[A-Z]+-[0-9]+")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(pullRequests) != 1 {
+		t.Fatalf("got %d pull requests, want 1", len(pullRequests))
+	}
+	pr := pullRequests[0]
+	if pr.ApprovalState != approvalUnknown || len(pr.Reviewers) != 0 || len(pr.Approvers) != 0 {
+		t.Errorf("pull request = %+v, want unknown approval without reviewers or approvers", pr)
+	}
+	if strings.Join(pr.JiraIDs, ",") != "EV-4" {
+		t.Errorf("ticket keys = %v, want EV-4", pr.JiraIDs)
+	}
+	if len(failures) != 2 || !strings.Contains(failures[0], "reviews of pull request #8") || !strings.Contains(failures[1], "commit bbb") {
+		t.Errorf("failures = %q, want the review and commit lookups", failures)
+	}
+}
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Redaction modes
+const (
+	redactDrop = "drop" // remove the value
+	redactHash = "hash" // replace with a salted HMAC-SHA256, stable across fields and runs with the same salt
+	redactMask = "mask" // keep the first character (and the email domain) only
+)
+
+// redactableFields are the field names accepted in the redaction configuration
+var redactableFields = []string{
+	"assignee", "reporter", "description",
+	"transition_author", "transition_author_email",
+	"commit_author", "commit_author_email",
+	"pr_author", "pr_reviewer",
+}
+
+// RedactionConfig maps redactable fields to a redaction mode
+type RedactionConfig struct {
+	Fields map[string]string `json:"fields"`
+}
+
+// RedactionInfo records in the evidence which redaction was applied, without the salt
+type RedactionInfo struct {
+	Fields    map[string]string `json:"fields"`
+	Algorithm string            `json:"algorithm,omitempty"`
+	Salted    bool              `json:"salted"`
+}
+
+// validate checks the configured field names and modes
+func (c RedactionConfig) validate() error {
+	for field, mode := range c.Fields {
+		if !containsFold(redactableFields, field) {
+			return fmt.Errorf("unknown redaction field %q, expected one of: %s", field, strings.Join(redactableFields, ", "))
+		}
+		switch mode {
+		case redactDrop, redactHash, redactMask:
+		default:
+			return fmt.Errorf("unsupported redaction mode %q for %s, expected drop, hash or mask", mode, field)
+		}
+	}
+	return nil
+}
+
+// redactor applies the configured modes and remembers every replacement so free text can be scrubbed too
+type redactor struct {
+	config       RedactionConfig
+	salt         []byte
+	replacements map[string]string
+}
+
+// redact returns the redacted form of value for the given field
+func (r *redactor) redact(field, value string) string {
+	mode, ok := r.config.Fields[field]
+	if !ok || value == "" {
+		return value
+	}
+
+	var redacted string
+	switch mode {
+	case redactDrop:
+		redacted = ""
+	case redactHash:
+		mac := hmac.New(sha256.New, r.salt)
+		mac.Write([]byte(value))
+		redacted = "sha256:" + hex.EncodeToString(mac.Sum(nil))[:16]
+	case redactMask:
+		redacted = maskValue(value)
+	}
+
+	// Descriptions are not scrubbed from other text; names and emails are
+	if field != "description" {
+		r.replacements[value] = redacted
+	}
+	return redacted
+}
+
+// redactList redacts every value of a list, leaving out dropped values
+func (r *redactor) redactList(field string, values []string) []string {
+	redacted := make([]string, 0, len(values))
+	for _, value := range values {
+		if value = r.redact(field, value); value != "" {
+			redacted = append(redacted, value)
+		}
+	}
+	return redacted
+}
+
+// maskValue keeps the first character of a name, or of the local part of an email
+func maskValue(value string) string {
+	_, size := utf8.DecodeRuneInString(value)
+	if at := strings.LastIndex(value, "@"); at > 0 {
+		return value[:size] + "***" + value[at:]
+	}
+	return value[:size] + "***"
+}
+
+// scrub replaces every redacted value occurring in free text, longest first so emails win over names
+func (r *redactor) scrub(text string) string {
+	values := make([]string, 0, len(r.replacements))
+	for value := range r.replacements {
+		values = append(values, value)
+	}
+	sort.Slice(values, func(i, j int) bool { return len(values[i]) > len(values[j]) })
+
+	for _, value := range values {
+		replacement := r.replacements[value]
+		if replacement == "" {
+			replacement = "[redacted]"
+		}
+		text = strings.ReplaceAll(text, value, replacement)
+	}
+	return text
+}
+
+// ApplyRedaction redacts personal data in place so every output format (JSON, CSV, markdown, HTML)
+// is built from the same redacted evidence, and records the redaction policy in the evidence
+func ApplyRedaction(data *TransitionCheckResponse, config RedactionConfig) {
+	if len(config.Fields) == 0 {
+		return
+	}
+
+	r := &redactor{config: config, replacements: make(map[string]string)}
+	info := &RedactionInfo{Fields: config.Fields}
+	for _, mode := range config.Fields {
+		if mode == redactHash {
+			info.Algorithm = "hmac-sha256"
+			salt := os.Getenv("JIRA_REDACTION_SALT")
+			if salt == "" {
+				fmt.Fprintln(os.Stderr, "Warning: JIRA_REDACTION_SALT is not set, hashed values can be reversed by guessing")
+			}
+			r.salt = []byte(salt)
+			info.Salted = salt != ""
+			break
+		}
+	}
+
+	for i := range data.Tasks {
+		task := &data.Tasks[i]
+		if task.Type == "Error" {
+			continue
+		}
+
+		if task.Assignee != nil {
+			assignee := r.redact("assignee", *task.Assignee)
+			if assignee == "" {
+				task.Assignee = nil
+			} else {
+				task.Assignee = &assignee
+			}
+		}
+		task.Reporter = r.redact("reporter", task.Reporter)
+		task.Description = r.redact("description", task.Description)
+
+		for j := range task.Transitions {
+			transition := &task.Transitions[j]
+			transition.Author = r.redact("transition_author", transition.Author)
+			transition.AuthorEmail = r.redact("transition_author_email", transition.AuthorEmail)
+		}
+		if task.Timeline != nil {
+			for j := range task.Timeline.Periods {
+				period := &task.Timeline.Periods[j]
+				period.EnteredBy = r.redact("transition_author", period.EnteredBy)
+			}
+		}
+	}
+
+	for i := range data.Commits {
+		commit := &data.Commits[i]
+		commit.Author = r.redact("commit_author", commit.Author)
+		commit.AuthorEmail = r.redact("commit_author_email", commit.AuthorEmail)
+	}
+
+	for i := range data.PullRequests {
+		pr := &data.PullRequests[i]
+		pr.Author = r.redact("pr_author", pr.Author)
+		pr.Reviewers = r.redactList("pr_reviewer", pr.Reviewers)
+		pr.Approvers = r.redactList("pr_reviewer", pr.Approvers)
+	}
+
+	// Policy messages may quote names and emails (e.g. segregation-of-duties findings)
+	if data.Policy != nil {
+		for i := range data.Policy.Results {
+			data.Policy.Results[i].Message = r.scrub(data.Policy.Results[i].Message)
+		}
+	}
+
+	data.Redaction = info
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestMaskValue(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"Jane Doe", "J***"},
+		{"jane.doe@example.com", "j***@example.com"},
+		{"Émile Zola", "É***"},
+		{"łukasz@example.pl", "ł***@example.pl"},
+		{"@example.com", "@***"},
+	}
+	for _, tt := range tests {
+		got := maskValue(tt.value)
+		if got != tt.want {
+			t.Errorf("maskValue(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+		if !utf8.ValidString(got) {
+			t.Errorf("maskValue(%q) = %q is not valid UTF-8", tt.value, got)
+		}
+	}
+}
+
+func TestRedact(t *testing.T) {
+	config := RedactionConfig{Fields: map[string]string{
+		"assignee":                "drop",
+		"reporter":                "mask",
+		"transition_author_email": "hash",
+	}}
+
+	tests := []struct {
+		name  string
+		field string
+		value string
+		want  string
+	}{
+		{"drop", "assignee", "Jane Doe", ""},
+		{"mask", "reporter", "Jane Doe", "J***"},
+		{"mask non-ASCII", "reporter", "Łukasz Nowak", "Ł***"},
+		{"hash", "transition_author_email", "jane@example.com", "sha256:"},
+		{"unconfigured field", "commit_author", "Jane Doe", "Jane Doe"},
+		{"empty value", "reporter", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &redactor{config: config, salt: []byte("salt"), replacements: make(map[string]string)}
+			got := r.redact(tt.field, tt.value)
+			if config.Fields[tt.field] == redactHash {
+				if !strings.HasPrefix(got, tt.want) || len(got) != len("sha256:")+16 {
+					t.Errorf("redact(%q, %q) = %q, want %s followed by 16 hex digits", tt.field, tt.value, got, tt.want)
+				}
+				return
+			}
+			if got != tt.want {
+				t.Errorf("redact(%q, %q) = %q, want %q", tt.field, tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedactHashSalt(t *testing.T) {
+	config := RedactionConfig{Fields: map[string]string{"commit_author_email": "hash"}}
+	hash := func(salt, value string) string {
+		r := &redactor{config: config, salt: []byte(salt), replacements: make(map[string]string)}
+		return r.redact("commit_author_email", value)
+	}
+
+	if hash("a", "jane@example.com") != hash("a", "jane@example.com") {
+		t.Error("hash is not stable for the same salt")
+	}
+	if hash("a", "jane@example.com") == hash("b", "jane@example.com") {
+		t.Error("hash does not depend on the salt")
+	}
+	if hash("a", "jane@example.com") == hash("a", "john@example.com") {
+		t.Error("different values hash to the same result")
+	}
+}
+
+func TestScrub(t *testing.T) {
+	tests := []struct {
+		name         string
+		replacements map[string]string
+		text         string
+		want         string
+	}{
+		{
+			name:         "email wins over name",
+			replacements: map[string]string{"jane": "j***", "jane@example.com": "j***@example.com"},
+			text:         "approved by jane@example.com",
+			want:         "approved by j***@example.com",
+		},
+		{
+			name:         "full name wins over first name",
+			replacements: map[string]string{"Jane": "x", "Jane Doe": "J***"},
+			text:         "Jane Doe authored abc1234",
+			want:         "J*** authored abc1234",
+		},
+		{
+			name:         "dropped value",
+			replacements: map[string]string{"Jane Doe": ""},
+			text:         "Jane Doe moved the ticket",
+			want:         "[redacted] moved the ticket",
+		},
+		{
+			name:         "no match",
+			replacements: map[string]string{"Jane Doe": "J***"},
+			text:         "no approval transition found",
+			want:         "no approval transition found",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &redactor{replacements: tt.replacements}
+			if got := r.scrub(tt.text); got != tt.want {
+				t.Errorf("scrub(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyRedaction(t *testing.T) {
+	assignee := "Jane Doe"
+	data := TransitionCheckResponse{
+		Tasks: []JiraTransitionResult{{
+			Key:      "EV-1",
+			Assignee: &assignee,
+			Reporter: "Émile Zola",
+			Transitions: []Transition{
+				{Author: "Jane Doe", AuthorEmail: "jane@example.com", ToStatus: "Done"},
+			},
+		}},
+		Commits:      []Commit{{Author: "Jane Doe", AuthorEmail: "jane@example.com"}},
+		PullRequests: []PullRequest{{Author: "jdoe", Reviewers: []string{"jdoe", "emile"}, Approvers: []string{"emile"}}},
+		Policy: &PolicyReport{Results: []PolicyResult{
+			{Policy: segregationOfDutiesPolicy, Key: "EV-1", Message: "Jane Doe authored abc1234 and moved the ticket In Review → Done"},
+		}},
+	}
+	config := RedactionConfig{Fields: map[string]string{
+		"assignee":          "drop",
+		"reporter":          "mask",
+		"transition_author": "mask",
+		"commit_author":     "mask",
+		"pr_reviewer":       "drop",
+	}}
+
+	ApplyRedaction(&data, config)
+
+	task := data.Tasks[0]
+	if task.Assignee != nil {
+		t.Errorf("dropped assignee = %q, want nil", *task.Assignee)
+	}
+	if task.Reporter != "É***" {
+		t.Errorf("reporter = %q, want %q", task.Reporter, "É***")
+	}
+	if task.Transitions[0].Author != "J***" || task.Transitions[0].AuthorEmail != "jane@example.com" {
+		t.Errorf("transition = %+v, want masked author and unchanged email", task.Transitions[0])
+	}
+	if data.Commits[0].Author != "J***" {
+		t.Errorf("commit author = %q, want %q", data.Commits[0].Author, "J***")
+	}
+	if len(data.PullRequests[0].Reviewers) != 0 || len(data.PullRequests[0].Approvers) != 0 {
+		t.Errorf("dropped reviewers = %v, approvers = %v, want none", data.PullRequests[0].Reviewers, data.PullRequests[0].Approvers)
+	}
+	if message := data.Policy.Results[0].Message; strings.Contains(message, "Jane") {
+		t.Errorf("policy message %q still contains the name", message)
+	}
+	if data.Redaction == nil || data.Redaction.Salted {
+		t.Errorf("redaction info = %+v, want recorded and unsalted", data.Redaction)
+	}
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	jira "github.com/andygrunwald/go-jira/v2/cloud"
+)
+
+// buildRemoteLinks returns the remote links for a build: the package version and, when known, the CI run.
+// The globalId identifies the package or run, so every build a ticket shipped in gets its own link.
+func buildRemoteLinks(build *BuildInfo) []jira.RemoteLink {
+	links := []jira.RemoteLink{{
+		GlobalID:     "jira-evidence:package:" + build.Package(),
+		Application:  &jira.RemoteLinkApplication{Type: "com.jfrog.artifactory", Name: "Artifactory"},
+		Relationship: "shipped in",
+		Object: &jira.RemoteLinkObject{
+			URL:     build.PackageURL(),
+			Title:   build.Package(),
+			Summary: fmt.Sprintf("Build %s", build.BuildNumber),
+		},
+	}}
+	if build.RunURL != "" {
+		links = append(links, jira.RemoteLink{
+			GlobalID:     "jira-evidence:run:" + build.RunURL,
+			Application:  &jira.RemoteLinkApplication{Type: "com.github.actions", Name: "CI"},
+			Relationship: "built by",
+			Object: &jira.RemoteLinkObject{
+				URL:     build.RunURL,
+				Title:   fmt.Sprintf("CI run for %s build %s", build.ImageName, build.BuildNumber),
+				Summary: build.Package(),
+			},
+		})
+	}
+	return links
+}
+
+// AddRemoteLinks adds the links to a ticket, updating links that already exist with the same globalId.
+// It returns the number of links created and updated.
+func (jc *JiraClient) AddRemoteLinks(key string, links []jira.RemoteLink) (int, int, error) {
+	ctx := context.Background()
+
+	existing, _, err := jc.client.Issue.GetRemoteLinks(ctx, key)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to get remote links: %v", err)
+	}
+	ids := make(map[string]int)
+	for _, link := range *existing {
+		if link.GlobalID != "" {
+			ids[link.GlobalID] = link.ID
+		}
+	}
+
+	created, updated := 0, 0
+	for i := range links {
+		link := &links[i]
+		if id, ok := ids[link.GlobalID]; ok {
+			resp, err := jc.client.Issue.UpdateRemoteLink(ctx, key, id, link)
+			if resp != nil {
+				resp.Body.Close()
+			}
+			if err != nil {
+				return created, updated, fmt.Errorf("failed to update remote link %s: %v", link.GlobalID, err)
+			}
+			updated++
+			continue
+		}
+		if _, _, err := jc.client.Issue.AddRemoteLink(ctx, key, link); err != nil {
+			return created, updated, fmt.Errorf("failed to add remote link %s: %v", link.GlobalID, err)
+		}
+		created++
+	}
+	return created, updated, nil
+}
+
+// runRemoteLink implements the remote-link subcommand: link every retrieved ticket to the package and CI run
+func runRemoteLink(args []string) int {
+	fs := flag.NewFlagSet("remote-link", flag.ExitOnError)
+	evidenceFile := fs.String("evidence", "", "Evidence JSON file written by the tool (default: $OUTPUT_FILE or transformed_jira_data.json)")
+	build := registerBuildFlags(fs)
+	dryRun := registerDryRunFlags(fs)
+	fs.Parse(args)
+
+	if err := build.resolve(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return exitUsage
+	}
+	if build.ArtifactoryURL == "" {
+		fmt.Fprintln(os.Stderr, "Error: --artifactory-url is required to link the package")
+		return exitUsage
+	}
+
+	data, err := readEvidence(evidencePath(*evidenceFile))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return exitError
+	}
+
+	jiraClient, err := dryRun.NewJiraClient()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating JIRA client: %v\n", err)
+		return exitError
+	}
+
+	failed := 0
+	for _, task := range data.Tasks {
+		if task.Type == "Error" {
+			continue
+		}
+		created, updated, err := jiraClient.AddRemoteLinks(task.Key, buildRemoteLinks(build))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %s: %v\n", task.Key, err)
+			failed++
+			continue
+		}
+		fmt.Printf("✅ %s: %d remote link(s) created, %d updated\n", task.Key, created, updated)
+	}
+
+	if err := dryRun.finish(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return exitError
+	}
+
+	if failed > 0 {
+		fmt.Fprintf(os.Stderr, "❌ Failed to link %d ticket(s)\n", failed)
+		return exitError
+	}
+	return exitSuccess
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/cel-go/cel"
+)
+
+// RuleConfig is a user-provided CEL rule evaluated against the evidence document.
+//
+// Document-scoped rules see the whole evidence as `evidence`; ticket-scoped rules are
+// evaluated once per task and additionally see the task as `ticket` and the commits
+// referencing it as `commits`. Field names are the JSON names used in the evidence.
+type RuleConfig struct {
+	Name       string `json:"name"`
+	Scope      string `json:"scope"`      // "document" (default) or "ticket"
+	Expression string `json:"expression"` // must evaluate to a bool; true means the rule passed
+	Message    string `json:"message"`    // recorded when the rule fails
+	Severity   string `json:"severity"`   // "error" (default) or "warning"
+}
+
+// compiledRule is a rule whose expression has been checked and planned
+type compiledRule struct {
+	config  RuleConfig
+	program cel.Program
+}
+
+// compileRules validates and compiles the configured rules
+func compileRules(rules []RuleConfig) ([]compiledRule, error) {
+	env, err := cel.NewEnv(
+		cel.Variable("evidence", cel.DynType),
+		cel.Variable("ticket", cel.DynType),
+		cel.Variable("commits", cel.ListType(cel.DynType)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create rule environment: %v", err)
+	}
+
+	var compiled []compiledRule
+	for i, rule := range rules {
+		if rule.Name == "" {
+			return nil, fmt.Errorf("rule %d has no name", i+1)
+		}
+		switch rule.Scope {
+		case "":
+			rule.Scope = "document"
+		case "document", "ticket":
+		default:
+			return nil, fmt.Errorf("rule %s: unsupported scope %q, expected document or ticket", rule.Name, rule.Scope)
+		}
+		switch rule.Severity {
+		case "":
+			rule.Severity = severityError
+		case severityError, severityWarning:
+		default:
+			return nil, fmt.Errorf("rule %s: unsupported severity %q, expected error or warning", rule.Name, rule.Severity)
+		}
+
+		ast, issues := env.Compile(rule.Expression)
+		if issues != nil && issues.Err() != nil {
+			return nil, fmt.Errorf("rule %s: %v", rule.Name, issues.Err())
+		}
+		program, err := env.Program(ast)
+		if err != nil {
+			return nil, fmt.Errorf("rule %s: %v", rule.Name, err)
+		}
+
+		compiled = append(compiled, compiledRule{config: rule, program: program})
+	}
+
+	return compiled, nil
+}
+
+// toDocument converts a value to its generic JSON form so rules see the same field names as the evidence
+func toDocument(value interface{}) (interface{}, error) {
+	jsonBytes, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var document interface{}
+	if err := json.Unmarshal(jsonBytes, &document); err != nil {
+		return nil, err
+	}
+	return document, nil
+}
+
+// evaluate runs a rule against one set of variables and turns the outcome into a policy result
+func (rule compiledRule) evaluate(key string, vars map[string]interface{}) PolicyResult {
+	result := PolicyResult{Policy: rule.config.Name, Key: key, Severity: rule.config.Severity}
+
+	out, _, err := rule.program.Eval(vars)
+	if err != nil {
+		result.Message = fmt.Sprintf("rule evaluation failed: %v", err)
+		return result
+	}
+
+	passed, ok := out.Value().(bool)
+	if !ok {
+		result.Message = fmt.Sprintf("rule must evaluate to a bool, got %v", out.Type())
+		return result
+	}
+
+	result.Passed = passed
+	if passed {
+		result.Message = "rule passed"
+	} else if rule.config.Message != "" {
+		result.Message = rule.config.Message
+	} else {
+		result.Message = fmt.Sprintf("rule failed: %s", rule.config.Expression)
+	}
+	return result
+}
+
+// evaluateRules runs every compiled rule against the evidence document
+func evaluateRules(data TransitionCheckResponse, rules []compiledRule) []PolicyResult {
+	var results []PolicyResult
+	if len(rules) == 0 {
+		return results
+	}
+
+	document, err := toDocument(data)
+	if err != nil {
+		for _, rule := range rules {
+			results = append(results, PolicyResult{
+				Policy:   rule.config.Name,
+				Severity: rule.config.Severity,
+				Message:  fmt.Sprintf("failed to prepare evidence document: %v", err),
+			})
+		}
+		return results
+	}
+
+	tickets, _ := document.(map[string]interface{})["tasks"].([]interface{})
+	commits, _ := document.(map[string]interface{})["commits"].([]interface{})
+	if commits == nil {
+		commits = []interface{}{}
+	}
+
+	for _, rule := range rules {
+		if rule.config.Scope == "document" {
+			results = append(results, rule.evaluate("", map[string]interface{}{
+				"evidence": document,
+				"ticket":   nil,
+				"commits":  commits,
+			}))
+			continue
+		}
+
+		for i, ticket := range tickets {
+			key := data.Tasks[i].Key
+			ticketCommits := []interface{}{}
+			for j, commit := range data.Commits {
+				if containsFold(commit.JiraIDs, key) {
+					ticketCommits = append(ticketCommits, commits[j])
+				}
+			}
+			results = append(results, rule.evaluate(key, map[string]interface{}{
+				"evidence": document,
+				"ticket":   ticket,
+				"commits":  ticketCommits,
+			}))
+		}
+	}
+
+	return results
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCompileRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    RuleConfig
+		wantErr string
+	}{
+		{"valid document rule", RuleConfig{Name: "has-tickets", Expression: "size(evidence.tasks) > 0"}, ""},
+		{"valid ticket rule", RuleConfig{Name: "has-commits", Scope: "ticket", Expression: "size(commits) > 0", Severity: "warning"}, ""},
+		{"syntax error", RuleConfig{Name: "broken", Expression: "size(evidence.tasks) >"}, "rule broken"},
+		{"undeclared variable", RuleConfig{Name: "unknown", Expression: "release.approved"}, "undeclared reference"},
+		{"missing name", RuleConfig{Expression: "true"}, "has no name"},
+		{"unknown scope", RuleConfig{Name: "r", Scope: "commit", Expression: "true"}, "unsupported scope"},
+		{"unknown severity", RuleConfig{Name: "r", Expression: "true", Severity: "info"}, "unsupported severity"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			compiled, err := compileRules([]RuleConfig{tt.rule})
+			if tt.wantErr == "" {
+				if err != nil || len(compiled) != 1 {
+					t.Fatalf("compileRules() = %v, %v, want one rule", compiled, err)
+				}
+				if compiled[0].config.Scope == "" || compiled[0].config.Severity == "" {
+					t.Errorf("defaults not applied: %+v", compiled[0].config)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("compileRules() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// mustCompileRules compiles rules for a test
+func mustCompileRules(t *testing.T, rules ...RuleConfig) []compiledRule {
+	t.Helper()
+	compiled, err := compileRules(rules)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return compiled
+}
+
+func TestEvaluateRulesDocumentScope(t *testing.T) {
+	data := TransitionCheckResponse{
+		Tasks:   []JiraTransitionResult{storyTicket("EV-1", "Done"), storyTicket("EV-2", "In Progress")},
+		Commits: []Commit{commitBy("abc1234def", "Jane Doe", "", "EV-1")},
+	}
+
+	tests := []struct {
+		name       string
+		rule       RuleConfig
+		wantPassed bool
+		wantMsg    string
+	}{
+		{"true", RuleConfig{Name: "r", Expression: "size(evidence.tasks) == 2 && size(commits) == 1"}, true, "rule passed"},
+		{"false with message", RuleConfig{Name: "r", Expression: "evidence.tasks.all(t, t.status == 'Done')", Message: "every ticket must be done"}, false, "every ticket must be done"},
+		{"false without message", RuleConfig{Name: "r", Expression: "size(commits) > 1"}, false, "rule failed: size(commits) > 1"},
+		{"not a bool", RuleConfig{Name: "r", Expression: "size(evidence.tasks)"}, false, "must evaluate to a bool"},
+		{"evaluation error", RuleConfig{Name: "r", Expression: "evidence.release.approved"}, false, "rule evaluation failed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := evaluateRules(data, mustCompileRules(t, tt.rule))
+			if len(results) != 1 {
+				t.Fatalf("got %d results, want 1", len(results))
+			}
+			result := results[0]
+			if result.Passed != tt.wantPassed || result.Key != "" || result.Severity != severityError {
+				t.Errorf("result = %+v, want passed %v without a key", result, tt.wantPassed)
+			}
+			if !strings.Contains(result.Message, tt.wantMsg) {
+				t.Errorf("message %q does not contain %q", result.Message, tt.wantMsg)
+			}
+		})
+	}
+}
+
+func TestEvaluateRulesTicketScope(t *testing.T) {
+	data := TransitionCheckResponse{
+		Tasks: []JiraTransitionResult{storyTicket("EV-1", "Done"), storyTicket("EV-2", "In Progress"), storyTicket("EV-3", "Done")},
+		Commits: []Commit{
+			commitBy("abc1234def", "Jane Doe", "", "EV-1"),
+			commitBy("def5678abc", "John Roe", "", "ev-1", "EV-3"),
+		},
+	}
+	rules := mustCompileRules(t,
+		RuleConfig{Name: "done", Scope: "ticket", Expression: "ticket.status == 'Done'", Severity: "warning"},
+		RuleConfig{Name: "reviewed", Scope: "ticket", Expression: "size(commits) >= 2"},
+	)
+
+	results := evaluateRules(data, rules)
+
+	want := []struct {
+		policy string
+		key    string
+		passed bool
+	}{
+		{"done", "EV-1", true}, {"done", "EV-2", false}, {"done", "EV-3", true},
+		{"reviewed", "EV-1", true}, {"reviewed", "EV-2", false}, {"reviewed", "EV-3", false},
+	}
+	if len(results) != len(want) {
+		t.Fatalf("got %d results, want %d", len(results), len(want))
+	}
+	for i, w := range want {
+		if results[i].Policy != w.policy || results[i].Key != w.key || results[i].Passed != w.passed {
+			t.Errorf("result %d = %+v, want %s %s passed %v", i, results[i], w.policy, w.key, w.passed)
+		}
+	}
+
+	addPolicyResults(&data, results[:3]...)
+	if !data.Policy.Passed {
+		t.Errorf("a failed warning rule failed the policy")
+	}
+	addPolicyResults(&data, results[3:]...)
+	if data.Policy.Passed {
+		t.Errorf("a failed error rule did not fail the policy")
+	}
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// securityClassification is the classification tag added to security-sensitive tickets
+const securityClassification = "security"
+
+// securityReviewPolicy is the policy name recorded for the security review check
+const securityReviewPolicy = "security-review"
+
+// redactedSecurityDescription replaces the description of security tickets when redaction is enabled
+const redactedSecurityDescription = "[redacted: security-sensitive ticket]"
+
+// SecurityConfig configures how security-sensitive tickets are recognized and handled
+type SecurityConfig struct {
+	Labels            []string `json:"labels"`             // e.g. ["security"]
+	Types             []string `json:"types"`              // e.g. ["Vulnerability"]
+	KeyPatterns       []string `json:"key_patterns"`       // regexes matched against linked issue keys and labels, e.g. "CVE-\\d{4}-\\d+"
+	RedactDescription bool     `json:"redact_description"` // replace the description of security tickets
+	ReviewStatuses    []string `json:"review_statuses"`    // a security ticket must have entered one of these statuses
+	Reviewers         []string `json:"reviewers"`          // optional emails or names allowed to make the review transition
+}
+
+// enabled reports whether any classifier is configured
+func (c SecurityConfig) enabled() bool {
+	return len(c.Labels) > 0 || len(c.Types) > 0 || len(c.KeyPatterns) > 0
+}
+
+// compileKeyPatterns validates the configured key patterns
+func (c SecurityConfig) compileKeyPatterns() ([]*regexp.Regexp, error) {
+	var patterns []*regexp.Regexp
+	for _, pattern := range c.KeyPatterns {
+		regex, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid security key pattern %q: %v", pattern, err)
+		}
+		patterns = append(patterns, regex)
+	}
+	return patterns, nil
+}
+
+// isSecurityTicket reports whether a ticket matches any of the configured classifiers
+func isSecurityTicket(task JiraTransitionResult, config SecurityConfig, patterns []*regexp.Regexp) bool {
+	if containsFold(config.Types, task.Type) {
+		return true
+	}
+	for _, label := range task.Labels {
+		if containsFold(config.Labels, label) {
+			return true
+		}
+	}
+	for _, pattern := range patterns {
+		for _, value := range append(append([]string{}, task.Links...), task.Labels...) {
+			if pattern.MatchString(value) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ClassifySecurityTickets tags security-sensitive tickets, counts them in the summary
+// and redacts their descriptions when configured
+func ClassifySecurityTickets(data *TransitionCheckResponse, config SecurityConfig) error {
+	if !config.enabled() {
+		return nil
+	}
+
+	patterns, err := config.compileKeyPatterns()
+	if err != nil {
+		return err
+	}
+
+	count := 0
+	for i := range data.Tasks {
+		task := &data.Tasks[i]
+		if task.Type == "Error" || !isSecurityTicket(*task, config, patterns) {
+			continue
+		}
+
+		count++
+		task.Classifications = append(task.Classifications, securityClassification)
+		if config.RedactDescription {
+			task.Description = redactedSecurityDescription
+		}
+	}
+
+	if data.Summary == nil {
+		data.Summary = &EvidenceSummary{}
+	}
+	data.Summary.SecurityTickets = count
+
+	return nil
+}
+
+// checkSecurityReview verifies that every security ticket went through a security review status,
+// optionally moved there by one of the configured reviewers
+func checkSecurityReview(tasks []JiraTransitionResult, config SecurityConfig) []PolicyResult {
+	var results []PolicyResult
+	for _, task := range tasks {
+		if !containsFold(task.Classifications, securityClassification) {
+			continue
+		}
+
+		result := PolicyResult{Policy: securityReviewPolicy, Key: task.Key, Severity: severityError}
+		var unauthorized []string
+		for _, transition := range task.Transitions {
+			if !containsFold(config.ReviewStatuses, transition.ToStatus) {
+				continue
+			}
+			if len(config.Reviewers) == 0 || containsFold(config.Reviewers, transition.AuthorEmail) || containsFold(config.Reviewers, transition.Author) {
+				result.Passed = true
+				result.Message = fmt.Sprintf("moved to %s by %s", transition.ToStatus, transition.Author)
+				break
+			}
+			unauthorized = append(unauthorized, transition.Author)
+		}
+
+		if !result.Passed {
+			if len(unauthorized) > 0 {
+				result.Message = fmt.Sprintf("moved to a security review status only by non-reviewers: %s", strings.Join(unauthorized, ", "))
+			} else {
+				result.Message = fmt.Sprintf("security ticket never entered a review status (%s)", strings.Join(config.ReviewStatuses, ", "))
+			}
+		}
+
+		results = append(results, result)
+	}
+	return results
+}
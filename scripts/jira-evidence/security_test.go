@@ -0,0 +1,123 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestIsSecurityTicket(t *testing.T) {
+	config := SecurityConfig{
+		Labels:      []string{"security"},
+		Types:       []string{"Vulnerability"},
+		KeyPatterns: []string{`^CVE-\d{4}-\d+$`},
+	}
+	patterns, err := config.compileKeyPatterns()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	vulnerability := storyTicket("EV-1", "Done")
+	vulnerability.Type = "vulnerability"
+	labelled := storyTicket("EV-2", "Done")
+	labelled.Labels = []string{"backend", "Security"}
+	linked := storyTicket("EV-3", "Done")
+	linked.Links = []string{"EV-9", "CVE-2024-12345"}
+	cveLabel := storyTicket("EV-4", "Done")
+	cveLabel.Labels = []string{"CVE-2023-1"}
+	plain := storyTicket("EV-5", "Done")
+	plain.Labels = []string{"security-review-done", "CVE-2024"}
+	plain.Links = []string{"EV-9"}
+
+	tests := []struct {
+		name string
+		task JiraTransitionResult
+		want bool
+	}{
+		{"issue type, any case", vulnerability, true},
+		{"label, any case", labelled, true},
+		{"linked key pattern", linked, true},
+		{"label key pattern", cveLabel, true},
+		{"no classifier matches", plain, false},
+	}
+	for _, tt := range tests {
+		if got := isSecurityTicket(tt.task, config, patterns); got != tt.want {
+			t.Errorf("%s: isSecurityTicket() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestClassifySecurityTickets(t *testing.T) {
+	security := storyTicket("EV-1", "Done")
+	security.Labels = []string{"security"}
+	security.Description = "exploit details"
+	unretrieved := JiraTransitionResult{Key: "EV-2", Type: "Error", Labels: []string{"security"}}
+	plain := storyTicket("EV-3", "Done")
+	plain.Description = "feature"
+	data := TransitionCheckResponse{Tasks: []JiraTransitionResult{security, unretrieved, plain}}
+
+	if err := ClassifySecurityTickets(&data, SecurityConfig{Labels: []string{"security"}, RedactDescription: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	if !containsFold(data.Tasks[0].Classifications, securityClassification) || data.Tasks[0].Description != redactedSecurityDescription {
+		t.Errorf("security ticket = %+v, want classified and redacted", data.Tasks[0])
+	}
+	if len(data.Tasks[1].Classifications) != 0 || len(data.Tasks[2].Classifications) != 0 || data.Tasks[2].Description != "feature" {
+		t.Errorf("other tickets were classified or changed: %+v", data.Tasks[1:])
+	}
+	if data.Summary == nil || data.Summary.SecurityTickets != 1 {
+		t.Errorf("summary = %+v, want one security ticket", data.Summary)
+	}
+
+	if err := ClassifySecurityTickets(&data, SecurityConfig{KeyPatterns: []string{"CVE-("}}); err == nil {
+		t.Error("invalid key pattern was accepted")
+	}
+}
+
+func TestCheckSecurityReview(t *testing.T) {
+	reviewed := func(transitions ...Transition) JiraTransitionResult {
+		task := storyTicket("EV-1", "Done", transitions...)
+		task.Classifications = []string{securityClassification}
+		return task
+	}
+	review := func(author, email string) Transition {
+		transition := statusChange("In Progress", "Security Review", author, "2024-01-02T10:00:00.000+0000")
+		transition.AuthorEmail = email
+		return transition
+	}
+	done := statusChange("Security Review", "Done", "Jane Doe", "2024-01-03T10:00:00.000+0000")
+
+	tests := []struct {
+		name       string
+		reviewers  []string
+		task       JiraTransitionResult
+		wantPassed bool
+		wantInMsg  string
+	}{
+		{"review status entered", nil, reviewed(review("Jane Doe", ""), done), true, "moved to Security Review by Jane Doe"},
+		{"review status never entered", nil, reviewed(done), false, "never entered a review status (Security Review, AppSec Approved)"},
+		{"reviewer matched by email", []string{"sec@example.com"}, reviewed(review("Sam Sec", "SEC@example.com")), true, "by Sam Sec"},
+		{"reviewer matched by name", []string{"sam sec"}, reviewed(review("Sam Sec", "")), true, "by Sam Sec"},
+		{"moved by a non-reviewer", []string{"sec@example.com"}, reviewed(review("Jane Doe", "jane@example.com")), false, "only by non-reviewers: Jane Doe"},
+		{"a reviewer after a non-reviewer", []string{"sec@example.com"}, reviewed(review("Jane Doe", "jane@example.com"), review("Sam Sec", "sec@example.com")), true, "by Sam Sec"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := SecurityConfig{ReviewStatuses: []string{"Security Review", "AppSec Approved"}, Reviewers: tt.reviewers}
+			results := checkSecurityReview([]JiraTransitionResult{tt.task}, config)
+			if len(results) != 1 {
+				t.Fatalf("got %d results, want 1", len(results))
+			}
+			if results[0].Passed != tt.wantPassed || results[0].Policy != securityReviewPolicy || results[0].Severity != severityError {
+				t.Errorf("result = %+v, want passed %v", results[0], tt.wantPassed)
+			}
+			if !strings.Contains(results[0].Message, tt.wantInMsg) {
+				t.Errorf("message %q does not contain %q", results[0].Message, tt.wantInMsg)
+			}
+		})
+	}
+
+	if results := checkSecurityReview([]JiraTransitionResult{storyTicket("EV-2", "Done")}, SecurityConfig{ReviewStatuses: []string{"Security Review"}}); len(results) != 0 {
+		t.Errorf("results = %+v, want none for tickets that are not security tickets", results)
+	}
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// segregationOfDutiesPolicy is the policy name recorded for the segregation-of-duties check
+const segregationOfDutiesPolicy = "segregation-of-duties"
+
+// ApprovalTransition is a from/to status pair that counts as an approval; "*" matches any status
+type ApprovalTransition struct {
+	From string
+	To   string
+}
+
+// parseApprovalTransitions parses a comma-separated list such as "In Review->Approved,*->Done"
+func parseApprovalTransitions(value string) ([]ApprovalTransition, error) {
+	var transitions []ApprovalTransition
+	for _, entry := range parseColumns(value) {
+		parts := strings.Split(entry, "->")
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" || strings.TrimSpace(parts[1]) == "" {
+			return nil, fmt.Errorf("invalid approval transition %q, expected FROM->TO", entry)
+		}
+		transitions = append(transitions, ApprovalTransition{
+			From: strings.TrimSpace(parts[0]),
+			To:   strings.TrimSpace(parts[1]),
+		})
+	}
+	return transitions, nil
+}
+
+// matches reports whether a ticket transition is this approval transition
+func (a ApprovalTransition) matches(transition Transition) bool {
+	fromMatches := a.From == "*" || strings.EqualFold(a.From, transition.FromStatus)
+	toMatches := a.To == "*" || strings.EqualFold(a.To, transition.ToStatus)
+	return fromMatches && toMatches
+}
+
+// samePerson compares a commit author with a transition author, preferring email and
+// falling back to the display name when JIRA hides the email address
+func samePerson(commit Commit, transition Transition) bool {
+	if commit.AuthorEmail != "" && transition.AuthorEmail != "" {
+		return strings.EqualFold(commit.AuthorEmail, transition.AuthorEmail)
+	}
+	return commit.Author != "" && strings.EqualFold(strings.TrimSpace(commit.Author), strings.TrimSpace(transition.Author))
+}
+
+// commitsByTicket maps each ticket key to the commits referencing it, either in the commit message
+// or through a pull request that contains the commit
+func commitsByTicket(data TransitionCheckResponse) map[string][]Commit {
+	commitsByKey := make(map[string][]Commit)
+	seen := make(map[[2]string]bool)
+	add := func(key string, commit Commit) {
+		if !seen[[2]string{key, commit.Hash}] {
+			seen[[2]string{key, commit.Hash}] = true
+			commitsByKey[key] = append(commitsByKey[key], commit)
+		}
+	}
+
+	byHash := make(map[string]Commit, len(data.Commits))
+	for _, commit := range data.Commits {
+		byHash[commit.Hash] = commit
+		for _, jiraID := range commit.JiraIDs {
+			add(jiraID, commit)
+		}
+	}
+	for _, pr := range data.PullRequests {
+		for _, jiraID := range pr.JiraIDs {
+			for _, hash := range pr.Commits {
+				if commit, ok := byHash[hash]; ok {
+					add(jiraID, commit)
+				}
+			}
+		}
+	}
+	return commitsByKey
+}
+
+// notEvaluated records that a check could not run for a ticket, e.g. because no commit references it
+// in direct mode. It fails with the check's severity rather than passing silently.
+func notEvaluated(policy, key, severity, reason string) PolicyResult {
+	return PolicyResult{Policy: policy, Key: key, Severity: severity, Message: "not evaluated: " + reason}
+}
+
+// checkSegregationOfDuties flags tickets whose approval transition was made by one of the authors of the referencing commits,
+// tickets that were never approved and tickets without referencing commits
+func checkSegregationOfDuties(data TransitionCheckResponse, approvals []ApprovalTransition, enforce bool) []PolicyResult {
+	severity := severityWarning
+	if enforce {
+		severity = severityError
+	}
+
+	commitsByKey := commitsByTicket(data)
+
+	var results []PolicyResult
+	for _, task := range data.Tasks {
+		if task.Type == "Error" {
+			continue
+		}
+		commits := commitsByKey[task.Key]
+		if len(commits) == 0 {
+			results = append(results, notEvaluated(segregationOfDutiesPolicy, task.Key, severity, "no commit references this ticket"))
+			continue
+		}
+
+		result := PolicyResult{Policy: segregationOfDutiesPolicy, Key: task.Key, Passed: true, Severity: severity}
+		approved := false
+		var violations []string
+
+		for _, transition := range task.Transitions {
+			for _, approval := range approvals {
+				if !approval.matches(transition) {
+					continue
+				}
+				approved = true
+				for _, commit := range commits {
+					if samePerson(commit, transition) {
+						violations = append(violations, fmt.Sprintf("%s authored %s and moved the ticket %s → %s",
+							transition.Author, shortHash(commit.Hash), transition.FromStatus, transition.ToStatus))
+						break
+					}
+				}
+			}
+		}
+
+		switch {
+		case len(violations) > 0:
+			result.Passed = false
+			result.Message = strings.Join(violations, "; ")
+		case approved:
+			result.Message = "approval transitions were made by someone other than the commit authors"
+		default:
+			// An unapproved ticket does not satisfy the policy
+			result.Passed = false
+			result.Message = "no approval transition found"
+		}
+
+		results = append(results, result)
+	}
+
+	return results
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseApprovalTransitions(t *testing.T) {
+	approvals, err := parseApprovalTransitions("In Review->Approved, *->Done")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []ApprovalTransition{{From: "In Review", To: "Approved"}, {From: "*", To: "Done"}}
+	if len(approvals) != len(want) || approvals[0] != want[0] || approvals[1] != want[1] {
+		t.Errorf("parseApprovalTransitions() = %v, want %v", approvals, want)
+	}
+
+	for _, value := range []string{"Done", "In Review->", "->Done", "A->B->C"} {
+		if _, err := parseApprovalTransitions(value); err == nil {
+			t.Errorf("parseApprovalTransitions(%q) succeeded, want an error", value)
+		}
+	}
+}
+
+func TestCheckSegregationOfDuties(t *testing.T) {
+	approvals := []ApprovalTransition{{From: "In Review", To: "Approved"}, {From: "*", To: "Done"}}
+	approvedBy := func(author, email string) JiraTransitionResult {
+		approval := statusChange("In Review", "Approved", author, "2024-01-02T10:00:00.000+0000")
+		approval.AuthorEmail = email
+		return storyTicket("EV-1", "Approved", statusChange("To Do", "In Review", "Jane Doe", "2024-01-01T10:00:00.000+0000"), approval)
+	}
+
+	tests := []struct {
+		name       string
+		task       JiraTransitionResult
+		commits    []Commit
+		wantPassed bool
+		wantInMsg  string
+	}{
+		{
+			name:       "approved by someone else",
+			task:       approvedBy("John Roe", "john@example.com"),
+			commits:    []Commit{commitBy("abc1234def", "Jane Doe", "jane@example.com", "EV-1")},
+			wantPassed: true,
+		},
+		{
+			name:      "approved by the commit author, matched by email",
+			task:      approvedBy("J. Doe", "Jane@Example.com"),
+			commits:   []Commit{commitBy("abc1234def", "Jane Doe", "jane@example.com", "EV-1")},
+			wantInMsg: "J. Doe authored abc1234def and moved the ticket In Review → Approved",
+		},
+		{
+			name:       "same name but different email",
+			task:       approvedBy("Jane Doe", "jane.doe@other.example"),
+			commits:    []Commit{commitBy("abc1234def", "Jane Doe", "jane@example.com", "EV-1")},
+			wantPassed: true,
+		},
+		{
+			name:      "email hidden, matched by display name",
+			task:      approvedBy(" jane doe ", ""),
+			commits:   []Commit{commitBy("abc1234def", "Jane Doe", "jane@example.com", "EV-1")},
+			wantInMsg: "authored abc1234def",
+		},
+		{
+			name:      "one of several commit authors approved",
+			task:      approvedBy("John Roe", "john@example.com"),
+			commits:   []Commit{commitBy("abc1234def", "Jane Doe", "jane@example.com", "EV-1"), commitBy("def5678abc", "John Roe", "john@example.com", "EV-1")},
+			wantInMsg: "John Roe authored def5678abc",
+		},
+		{
+			name: "wildcard approval made by the commit author",
+			task: storyTicket("EV-1", "Done",
+				statusChange("In Progress", "Done", "Jane Doe", "2024-01-02T10:00:00.000+0000")),
+			commits:   []Commit{commitBy("abc1234def", "Jane Doe", "", "EV-1")},
+			wantInMsg: "In Progress → Done",
+		},
+		{
+			name: "never approved",
+			task: storyTicket("EV-1", "In Review",
+				statusChange("To Do", "In Review", "John Roe", "2024-01-02T10:00:00.000+0000")),
+			commits:   []Commit{commitBy("abc1234def", "Jane Doe", "jane@example.com", "EV-1")},
+			wantInMsg: "no approval transition found",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := TransitionCheckResponse{Tasks: []JiraTransitionResult{tt.task}, Commits: tt.commits}
+			results := checkSegregationOfDuties(data, approvals, false)
+			if len(results) != 1 {
+				t.Fatalf("got %d results, want 1", len(results))
+			}
+			result := results[0]
+			if result.Passed != tt.wantPassed || result.Policy != segregationOfDutiesPolicy || result.Key != "EV-1" {
+				t.Errorf("result = %+v, want passed %v", result, tt.wantPassed)
+			}
+			if !strings.Contains(result.Message, tt.wantInMsg) {
+				t.Errorf("message %q does not contain %q", result.Message, tt.wantInMsg)
+			}
+		})
+	}
+}
+
+func TestCheckSegregationOfDutiesEnforce(t *testing.T) {
+	data := TransitionCheckResponse{
+		Tasks: []JiraTransitionResult{storyTicket("EV-1", "Done",
+			statusChange("In Review", "Done", "Jane Doe", "2024-01-02T10:00:00.000+0000"))},
+		Commits: []Commit{commitBy("abc1234def", "Jane Doe", "", "EV-1")},
+	}
+	approvals := []ApprovalTransition{{From: "*", To: "Done"}}
+
+	for _, enforce := range []bool{false, true} {
+		data.Policy = nil
+		addPolicyResults(&data, checkSegregationOfDuties(data, approvals, enforce)...)
+		if data.Policy.Passed != !enforce {
+			t.Errorf("enforce %v: policy passed = %v, want %v", enforce, data.Policy.Passed, !enforce)
+		}
+	}
+}
+
+func TestCheckSegregationOfDutiesSkipsTickets(t *testing.T) {
+	unretrieved := JiraTransitionResult{Key: "EV-2", Type: "Error"}
+	data := TransitionCheckResponse{
+		Tasks:   []JiraTransitionResult{unretrieved},
+		Commits: []Commit{commitBy("def5678abc", "Jane Doe", "", "EV-2")},
+	}
+	if results := checkSegregationOfDuties(data, []ApprovalTransition{{From: "*", To: "Done"}}, true); len(results) != 0 {
+		t.Errorf("results = %+v, want none for unretrieved tickets", results)
+	}
+}
+
+func TestCheckSegregationOfDutiesWithoutCommits(t *testing.T) {
+	// Direct mode has no commits, so the check cannot pass
+	data := TransitionCheckResponse{Tasks: []JiraTransitionResult{storyTicket("EV-1", "Done",
+		statusChange("In Review", "Done", "John Roe", "2024-01-02T10:00:00.000+0000"))}}
+
+	for _, enforce := range []bool{false, true} {
+		results := checkSegregationOfDuties(data, []ApprovalTransition{{From: "*", To: "Done"}}, enforce)
+		if len(results) != 1 || results[0].Passed || !strings.HasPrefix(results[0].Message, "not evaluated") {
+			t.Fatalf("enforce %v: results = %+v, want one not evaluated result", enforce, results)
+		}
+		data.Policy = nil
+		addPolicyResults(&data, results...)
+		if data.Policy.Passed != !enforce {
+			t.Errorf("enforce %v: policy passed = %v, want %v", enforce, data.Policy.Passed, !enforce)
+		}
+	}
+}
+
+func TestCheckSegregationOfDutiesPullRequestKeys(t *testing.T) {
+	// The commit message has no key; the pull request containing the commit references the ticket
+	commit := commitBy("abc1234def", "Jane Doe", "jane@example.com")
+	approval := statusChange("In Review", "Done", "J. Doe", "2024-01-02T10:00:00.000+0000")
+	approval.AuthorEmail = "jane@example.com"
+	data := TransitionCheckResponse{
+		Tasks:        []JiraTransitionResult{storyTicket("EV-1", "Done", approval)},
+		Commits:      []Commit{commit},
+		PullRequests: []PullRequest{{Number: 7, Commits: []string{"abc1234def"}, JiraIDs: []string{"EV-1"}}},
+	}
+
+	results := checkSegregationOfDuties(data, []ApprovalTransition{{From: "*", To: "Done"}}, true)
+	if len(results) != 1 || results[0].Passed || !strings.Contains(results[0].Message, "authored abc1234def") {
+		t.Errorf("results = %+v, want a violation through the pull request", results)
+	}
+}
+
+func TestCommitsByTicket(t *testing.T) {
+	first := commitBy("abc1234def", "Jane Doe", "", "EV-1")
+	second := commitBy("def5678abc", "John Roe", "")
+	data := TransitionCheckResponse{
+		Commits: []Commit{first, second},
+		PullRequests: []PullRequest{
+			{Number: 7, Commits: []string{"abc1234def", "def5678abc"}, JiraIDs: []string{"EV-1", "EV-2"}},
+			{Number: 8, Commits: []string{"unknown"}, JiraIDs: []string{"EV-3"}},
+		},
+	}
+
+	commitsByKey := commitsByTicket(data)
+	hashes := func(key string) []string {
+		var list []string
+		for _, commit := range commitsByKey[key] {
+			list = append(list, commit.Hash)
+		}
+		return list
+	}
+	if got := hashes("EV-1"); len(got) != 2 || got[0] != "abc1234def" || got[1] != "def5678abc" {
+		t.Errorf("EV-1 commits = %v, want each commit once", got)
+	}
+	if got := hashes("EV-2"); len(got) != 2 {
+		t.Errorf("EV-2 commits = %v, want both pull request commits", got)
+	}
+	if got := hashes("EV-3"); len(got) != 0 {
+		t.Errorf("EV-3 commits = %v, want none for commits outside the range", got)
+	}
+}
@@ -0,0 +1,243 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	jira "github.com/andygrunwald/go-jira/v2/cloud"
+)
+
+// Timeline is the structured workflow history of a ticket, derived from its transitions
+type Timeline struct {
+	Periods          []StatusPeriod   `json:"periods"`
+	TimeInStatus     map[string]int64 `json:"time_in_status_seconds"`
+	CycleTimeSeconds *int64           `json:"cycle_time_seconds"`
+	LeadTimeSeconds  *int64           `json:"lead_time_seconds"`
+	ReopenCount      int              `json:"reopen_count"`
+}
+
+// StatusPeriod is one continuous stay of a ticket in a status; the current status has no exit time
+type StatusPeriod struct {
+	Status          string `json:"status"`
+	Entered         string `json:"entered"`
+	Exited          string `json:"exited,omitempty"`
+	DurationSeconds *int64 `json:"duration_seconds"`
+	EnteredBy       string `json:"entered_by,omitempty"`
+}
+
+// defaultDoneStatuses are the statuses treated as resolved unless JIRA_DONE_STATUSES is set
+var defaultDoneStatuses = []string{"Done", "Closed", "Resolved", "Released"}
+
+// jiraTimeLayouts are the timestamp formats returned by the JIRA API, which Artifactory build info uses too
+var jiraTimeLayouts = []string{
+	"2006-01-02T15:04:05.000-0700",
+	"2006-01-02T15:04:05-0700",
+	time.RFC3339Nano,
+	time.RFC3339,
+}
+
+// parseJiraTime parses a JIRA timestamp string
+func parseJiraTime(value string) (time.Time, error) {
+	for _, layout := range jiraTimeLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognized time format: %q", value)
+}
+
+// formatUTC normalizes a JIRA timestamp to UTC, returning "" when it cannot be parsed
+func formatUTC(value string) string {
+	t, err := parseJiraTime(value)
+	if err != nil {
+		return ""
+	}
+	return t.UTC().Format("2006-01-02T15:04:05.000Z")
+}
+
+// sortTransitions returns a copy of the transitions in chronological order.
+// Timestamps are compared as instants so differing timezone offsets order correctly;
+// transitions with unparseable timestamps keep their relative order after all others.
+func sortTransitions(transitions []Transition) []Transition {
+	sorted := make([]Transition, len(transitions))
+	copy(sorted, transitions)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		ti, errI := parseJiraTime(sorted[i].TransitionTime)
+		tj, errJ := parseJiraTime(sorted[j].TransitionTime)
+		if errI != nil || errJ != nil {
+			return errI == nil && errJ != nil
+		}
+		return ti.Before(tj)
+	})
+	return sorted
+}
+
+// sortHistories returns a copy of the changelog histories in chronological order.
+// Histories created in the same instant are ordered by their numeric ID, which JIRA assigns sequentially.
+func sortHistories(histories []jira.ChangelogHistory) []jira.ChangelogHistory {
+	sorted := make([]jira.ChangelogHistory, len(histories))
+	copy(sorted, histories)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		ti, errI := parseJiraTime(sorted[i].Created)
+		tj, errJ := parseJiraTime(sorted[j].Created)
+		if errI != nil || errJ != nil {
+			return errI == nil && errJ != nil
+		}
+		if !ti.Equal(tj) {
+			return ti.Before(tj)
+		}
+		idI, _ := strconv.Atoi(sorted[i].Id)
+		idJ, _ := strconv.Atoi(sorted[j].Id)
+		return idI < idJ
+	})
+	return sorted
+}
+
+// doneStatuses returns the set of statuses that mark a ticket as resolved
+func doneStatuses() map[string]bool {
+	statuses := defaultDoneStatuses
+	if env := os.Getenv("JIRA_DONE_STATUSES"); env != "" {
+		statuses = strings.Split(env, ",")
+	}
+
+	result := make(map[string]bool)
+	for _, status := range statuses {
+		result[strings.ToLower(strings.TrimSpace(status))] = true
+	}
+	return result
+}
+
+// buildTimeline turns the transitions of a ticket into status periods, cycle time, lead time and reopen count
+func buildTimeline(task JiraTransitionResult) *Timeline {
+	timeline := &Timeline{
+		Periods:      []StatusPeriod{},
+		TimeInStatus: make(map[string]int64),
+	}
+	if len(task.Transitions) == 0 {
+		return timeline
+	}
+
+	// Transitions with unparseable timestamps cannot be placed on the timeline and are skipped
+	type timedTransition struct {
+		Transition
+		at time.Time
+	}
+	var timed []timedTransition
+	for _, transition := range sortTransitions(task.Transitions) {
+		at, err := parseJiraTime(transition.TransitionTime)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: skipping transition of %s with %v\n", task.Key, err)
+			continue
+		}
+		timed = append(timed, timedTransition{Transition: transition, at: at})
+	}
+	if len(timed) == 0 {
+		return timeline
+	}
+
+	done := doneStatuses()
+	created, createdErr := parseJiraTime(task.Created)
+
+	// The ticket sits in the first transition's source status from creation until that transition
+	current := StatusPeriod{Status: timed[0].FromStatus, Entered: task.Created}
+	currentStart := created
+	hasStart := createdErr == nil
+
+	var workStarted, resolved time.Time
+	for i, transition := range timed {
+		current.Exited = transition.TransitionTime
+		if hasStart {
+			seconds := int64(transition.at.Sub(currentStart).Seconds())
+			current.DurationSeconds = &seconds
+			timeline.TimeInStatus[current.Status] += seconds
+		}
+		timeline.Periods = append(timeline.Periods, current)
+
+		if i == 0 {
+			workStarted = transition.at
+		}
+		if done[strings.ToLower(transition.FromStatus)] && !done[strings.ToLower(transition.ToStatus)] {
+			timeline.ReopenCount++
+			resolved = time.Time{}
+		}
+		if done[strings.ToLower(transition.ToStatus)] {
+			resolved = transition.at
+		}
+
+		current = StatusPeriod{
+			Status:    transition.ToStatus,
+			Entered:   transition.TransitionTime,
+			EnteredBy: transition.Author,
+		}
+		currentStart = transition.at
+		hasStart = true
+	}
+	timeline.Periods = append(timeline.Periods, current)
+
+	// Cycle and lead time are only meaningful once the ticket has been resolved
+	if !resolved.IsZero() {
+		cycle := int64(resolved.Sub(workStarted).Seconds())
+		timeline.CycleTimeSeconds = &cycle
+		if createdErr == nil {
+			lead := int64(resolved.Sub(created).Seconds())
+			timeline.LeadTimeSeconds = &lead
+		}
+	}
+
+	return timeline
+}
+
+// formatDuration renders a number of seconds as a compact human readable duration
+func formatDuration(seconds *int64) string {
+	if seconds == nil {
+		return "-"
+	}
+
+	d := time.Duration(*seconds) * time.Second
+	days := int(d.Hours()) / 24
+	hours := int(d.Hours()) % 24
+	minutes := int(d.Minutes()) % 60
+
+	switch {
+	case days > 0:
+		return fmt.Sprintf("%dd %dh %dm", days, hours, minutes)
+	case hours > 0:
+		return fmt.Sprintf("%dh %dm", hours, minutes)
+	default:
+		return fmt.Sprintf("%dm", minutes)
+	}
+}
+
+// generateTimelineMarkdown renders the per-ticket timelines as markdown sections
+func generateTimelineMarkdown(tasks []JiraTransitionResult) string {
+	content := "## Workflow Timelines\n"
+
+	for _, task := range tasks {
+		if task.Timeline == nil || len(task.Timeline.Periods) == 0 {
+			continue
+		}
+		timeline := task.Timeline
+
+		content += fmt.Sprintf("\n### %s\n", escapeMarkdown(task.Key))
+		content += fmt.Sprintf("Cycle time: %s · Lead time: %s · Reopened: %d\n\n",
+			formatDuration(timeline.CycleTimeSeconds), formatDuration(timeline.LeadTimeSeconds), timeline.ReopenCount)
+
+		content += "| Status | Entered | Exited | Time in Status | Moved By |\n"
+		content += "|--------|---------|--------|----------------|----------|\n"
+		for _, period := range timeline.Periods {
+			exited := period.Exited
+			if exited == "" {
+				exited = "current"
+			}
+			content += fmt.Sprintf("| %s | %s | %s | %s | %s |\n",
+				escapeMarkdown(period.Status), period.Entered, exited,
+				formatDuration(period.DurationSeconds), escapeMarkdown(period.EnteredBy))
+		}
+	}
+
+	return content
+}
@@ -0,0 +1,300 @@
+package main
+
+import (
+	"testing"
+
+	jira "github.com/andygrunwald/go-jira/v2/cloud"
+)
+
+func TestFormatUTC(t *testing.T) {
+	tests := []struct {
+		layout string
+		value  string
+		want   string
+	}{
+		{"2006-01-02T15:04:05.000-0700", "2024-03-10T08:15:30.250+0300", "2024-03-10T05:15:30.250Z"},
+		{"2006-01-02T15:04:05-0700", "2024-03-10T08:15:30-0500", "2024-03-10T13:15:30.000Z"},
+		{"RFC3339Nano", "2024-03-10T08:15:30.123456789+01:00", "2024-03-10T07:15:30.123Z"},
+		{"RFC3339", "2024-03-10T08:15:30Z", "2024-03-10T08:15:30.000Z"},
+		{"unparseable", "10/03/2024 08:15", ""},
+		{"empty", "", ""},
+	}
+	if len(jiraTimeLayouts) != 4 {
+		t.Fatalf("jiraTimeLayouts has %d entries, the cases below cover 4", len(jiraTimeLayouts))
+	}
+	for _, tt := range tests {
+		if got := formatUTC(tt.value); got != tt.want {
+			t.Errorf("formatUTC(%q) [%s] = %q, want %q", tt.value, tt.layout, got, tt.want)
+		}
+	}
+}
+
+// transitionOrder returns the target statuses of the transitions in order
+func transitionOrder(transitions []Transition) string {
+	order := ""
+	for _, transition := range transitions {
+		order += transition.ToStatus
+	}
+	return order
+}
+
+func TestSortTransitions(t *testing.T) {
+	tests := []struct {
+		name        string
+		transitions []Transition
+		want        string
+	}{
+		{
+			// As strings "10:00+0300" sorts after "06:00-0500", but 07:00Z is before 11:00Z
+			name: "different offsets",
+			transitions: []Transition{
+				statusChange("", "B", "", "2024-03-10T06:00:00.000-0500"),
+				statusChange("", "A", "", "2024-03-10T10:00:00.000+0300"),
+			},
+			want: "AB",
+		},
+		{
+			name: "mixed layouts",
+			transitions: []Transition{
+				statusChange("", "C", "", "2024-03-10T12:00:00Z"),
+				statusChange("", "B", "", "2024-03-10T11:00:00-0000"),
+				statusChange("", "A", "", "2024-03-10T10:00:00.000+0000"),
+			},
+			want: "ABC",
+		},
+		{
+			name: "unparseable timestamps last in stable order",
+			transitions: []Transition{
+				statusChange("", "X", "", "yesterday"),
+				statusChange("", "B", "", "2024-03-10T11:00:00.000+0000"),
+				statusChange("", "Y", "", ""),
+				statusChange("", "A", "", "2024-03-10T10:00:00.000+0000"),
+				statusChange("", "Z", "", "soon"),
+			},
+			want: "ABXYZ",
+		},
+		{
+			name: "equal instants keep their order",
+			transitions: []Transition{
+				statusChange("", "A", "", "2024-03-10T10:00:00.000+0000"),
+				statusChange("", "B", "", "2024-03-10T13:00:00.000+0300"),
+			},
+			want: "AB",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := transitionOrder(tt.transitions)
+			if got := transitionOrder(sortTransitions(tt.transitions)); got != tt.want {
+				t.Errorf("sortTransitions() order = %s, want %s", got, tt.want)
+			}
+			if transitionOrder(tt.transitions) != original {
+				t.Errorf("sortTransitions() reordered its input")
+			}
+		})
+	}
+}
+
+func TestSortHistories(t *testing.T) {
+	tests := []struct {
+		name      string
+		histories []jira.ChangelogHistory
+		want      []string
+	}{
+		{
+			name: "different offsets",
+			histories: []jira.ChangelogHistory{
+				{Id: "1", Created: "2024-03-10T06:00:00.000-0500"},
+				{Id: "2", Created: "2024-03-10T10:00:00.000+0300"},
+			},
+			want: []string{"2", "1"},
+		},
+		{
+			// "10" < "9" as strings; JIRA assigns history IDs sequentially
+			name: "same instant ordered by numeric id",
+			histories: []jira.ChangelogHistory{
+				{Id: "10", Created: "2024-03-10T10:00:00.000+0000"},
+				{Id: "9", Created: "2024-03-10T13:00:00.000+0300"},
+				{Id: "100", Created: "2024-03-10T10:00:00.000+0000"},
+			},
+			want: []string{"9", "10", "100"},
+		},
+		{
+			name: "unparseable timestamps last in stable order",
+			histories: []jira.ChangelogHistory{
+				{Id: "7", Created: "not a date"},
+				{Id: "3", Created: "2024-03-10T10:00:00.000+0000"},
+				{Id: "5", Created: ""},
+			},
+			want: []string{"3", "7", "5"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sorted := sortHistories(tt.histories)
+			for i, history := range sorted {
+				if history.Id != tt.want[i] {
+					t.Fatalf("sortHistories() position %d = %s, want order %v", i, history.Id, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestBuildTimelineAcrossOffsets(t *testing.T) {
+	// Listed as JIRA may return them: the later instant first because of its smaller offset string
+	task := storyTicket("EV-1", "Done",
+		statusChange("In Progress", "Done", "John Roe", "2024-01-01T08:00:00.000-0500"),
+		statusChange("To Do", "In Progress", "Jane Doe", "2024-01-01T12:00:00.000+0200"),
+	)
+
+	timeline := buildTimeline(task)
+
+	if len(timeline.Periods) != 3 || timeline.Periods[1].Status != "In Progress" {
+		t.Fatalf("periods = %+v, want To Do, In Progress, Done", timeline.Periods)
+	}
+	if timeline.TimeInStatus["In Progress"] != 3*3600 {
+		t.Errorf("time in In Progress = %d, want %d", timeline.TimeInStatus["In Progress"], 3*3600)
+	}
+}
+
+func TestBuildTimelineDurations(t *testing.T) {
+	task := storyTicket("EV-1", "Done",
+		statusChange("To Do", "In Progress", "Jane Doe", "2024-01-01T10:00:00.000+0000"),
+		statusChange("In Progress", "In Review", "Jane Doe", "2024-01-01T12:30:00.000+0000"),
+		statusChange("In Review", "Done", "John Roe", "2024-01-02T12:30:00.000+0000"),
+	)
+
+	timeline := buildTimeline(task)
+
+	want := map[string]int64{"To Do": 3600, "In Progress": 9000, "In Review": 86400}
+	for status, seconds := range want {
+		if timeline.TimeInStatus[status] != seconds {
+			t.Errorf("time in %s = %d, want %d", status, timeline.TimeInStatus[status], seconds)
+		}
+	}
+	if len(timeline.Periods) != 4 {
+		t.Fatalf("got %d periods, want 4", len(timeline.Periods))
+	}
+	current := timeline.Periods[3]
+	if current.Status != "Done" || current.Exited != "" || current.DurationSeconds != nil || current.EnteredBy != "John Roe" {
+		t.Errorf("current period = %+v, want Done entered by John Roe without exit", current)
+	}
+	if timeline.CycleTimeSeconds == nil || *timeline.CycleTimeSeconds != 95400 {
+		t.Errorf("cycle time = %v, want 95400", timeline.CycleTimeSeconds)
+	}
+	if timeline.LeadTimeSeconds == nil || *timeline.LeadTimeSeconds != 99000 {
+		t.Errorf("lead time = %v, want 99000", timeline.LeadTimeSeconds)
+	}
+	if timeline.ReopenCount != 0 {
+		t.Errorf("reopen count = %d, want 0", timeline.ReopenCount)
+	}
+}
+
+func TestBuildTimelineRepeatedStatus(t *testing.T) {
+	task := storyTicket("EV-1", "In Progress",
+		statusChange("To Do", "In Progress", "Jane Doe", "2024-01-01T10:00:00.000+0000"),
+		statusChange("In Progress", "In Review", "Jane Doe", "2024-01-01T11:00:00.000+0000"),
+		statusChange("In Review", "In Progress", "John Roe", "2024-01-01T11:30:00.000+0000"),
+	)
+
+	timeline := buildTimeline(task)
+
+	if timeline.TimeInStatus["In Progress"] != 3600 {
+		t.Errorf("time in In Progress = %d, want 3600 from the first stay only", timeline.TimeInStatus["In Progress"])
+	}
+	if timeline.CycleTimeSeconds != nil || timeline.LeadTimeSeconds != nil {
+		t.Errorf("unresolved ticket has cycle time %v and lead time %v, want none", timeline.CycleTimeSeconds, timeline.LeadTimeSeconds)
+	}
+}
+
+func TestBuildTimelineReopenCount(t *testing.T) {
+	tests := []struct {
+		name         string
+		transitions  []Transition
+		wantReopened int
+		wantResolved bool
+	}{
+		{
+			name: "never resolved",
+			transitions: []Transition{
+				statusChange("To Do", "In Progress", "Jane Doe", "2024-01-01T10:00:00.000+0000"),
+			},
+		},
+		{
+			name: "resolved once",
+			transitions: []Transition{
+				statusChange("To Do", "Done", "Jane Doe", "2024-01-01T10:00:00.000+0000"),
+			},
+			wantResolved: true,
+		},
+		{
+			name: "reopened and resolved again",
+			transitions: []Transition{
+				statusChange("To Do", "Done", "Jane Doe", "2024-01-01T10:00:00.000+0000"),
+				statusChange("Done", "In Progress", "John Roe", "2024-01-02T10:00:00.000+0000"),
+				statusChange("In Progress", "Closed", "Jane Doe", "2024-01-03T10:00:00.000+0000"),
+			},
+			wantReopened: 1,
+			wantResolved: true,
+		},
+		{
+			name: "reopened twice and left open",
+			transitions: []Transition{
+				statusChange("To Do", "Done", "Jane Doe", "2024-01-01T10:00:00.000+0000"),
+				statusChange("Done", "To Do", "John Roe", "2024-01-02T10:00:00.000+0000"),
+				statusChange("To Do", "Resolved", "Jane Doe", "2024-01-03T10:00:00.000+0000"),
+				statusChange("Resolved", "In Progress", "John Roe", "2024-01-04T10:00:00.000+0000"),
+			},
+			wantReopened: 2,
+		},
+		{
+			name: "moving between done statuses is not a reopen",
+			transitions: []Transition{
+				statusChange("To Do", "Done", "Jane Doe", "2024-01-01T10:00:00.000+0000"),
+				statusChange("Done", "Released", "John Roe", "2024-01-02T10:00:00.000+0000"),
+			},
+			wantResolved: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			timeline := buildTimeline(storyTicket("EV-1", "", tt.transitions...))
+			if timeline.ReopenCount != tt.wantReopened {
+				t.Errorf("reopen count = %d, want %d", timeline.ReopenCount, tt.wantReopened)
+			}
+			if (timeline.CycleTimeSeconds != nil) != tt.wantResolved {
+				t.Errorf("cycle time = %v, want resolved %v", timeline.CycleTimeSeconds, tt.wantResolved)
+			}
+		})
+	}
+}
+
+func TestBuildTimelineDoneStatusesOverride(t *testing.T) {
+	task := storyTicket("EV-1", "In Progress",
+		statusChange("To Do", "Shipped", "Jane Doe", "2024-01-01T10:00:00.000+0000"),
+		statusChange("Shipped", "In Progress", "John Roe", "2024-01-02T10:00:00.000+0000"),
+		statusChange("In Progress", "Done", "Jane Doe", "2024-01-03T10:00:00.000+0000"),
+	)
+
+	timeline := buildTimeline(task)
+	if timeline.ReopenCount != 0 || timeline.CycleTimeSeconds == nil || *timeline.CycleTimeSeconds != 172800 {
+		t.Errorf("default done statuses: reopen count %d, cycle time %v, want 0 and 172800", timeline.ReopenCount, timeline.CycleTimeSeconds)
+	}
+
+	t.Setenv("JIRA_DONE_STATUSES", " shipped ,Verified")
+	timeline = buildTimeline(task)
+	if timeline.ReopenCount != 1 {
+		t.Errorf("reopen count = %d, want 1 with Shipped as a done status", timeline.ReopenCount)
+	}
+	if timeline.CycleTimeSeconds != nil {
+		t.Errorf("cycle time = %d, want none once Done is no longer a done status", *timeline.CycleTimeSeconds)
+	}
+}
+
+func TestBuildTimelineWithoutTransitions(t *testing.T) {
+	timeline := buildTimeline(storyTicket("EV-1", "To Do"))
+	if len(timeline.Periods) != 0 || len(timeline.TimeInStatus) != 0 || timeline.CycleTimeSeconds != nil {
+		t.Errorf("timeline = %+v, want an empty timeline", timeline)
+	}
+}
@@ -0,0 +1,359 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Predicate types of the evidence produced by each tracker
+const (
+	jiraPredicateType     = "https://atlassian.com/jira/issues/v1"
+	githubPredicateType   = "https://github.com/issues/v1"
+	gitlabPredicateType   = "https://gitlab.com/issues/v1"
+	linearPredicateType   = "https://linear.app/issues/v1"
+	youtrackPredicateType = "https://www.jetbrains.com/youtrack/issues/v1"
+
+	// evidence from several trackers does not follow a single tracker's predicate
+	multiTrackerPredicateType = "https://jfrog.com/evidence/issue-trackers/v1"
+	multiTrackerProviderID    = "multi-tracker"
+)
+
+// IssueTracker fetches tickets from an issue tracker into the evidence structure.
+// Tickets that cannot be retrieved are returned as "Error" tasks, as FetchJiraDetails does.
+type IssueTracker interface {
+	FetchDetails(ids []string) TransitionCheckResponse
+}
+
+// trackerBackend describes a supported issue tracker
+type trackerBackend struct {
+	DisplayName    string                                            // tracker name used in messages
+	DefaultIDRegex string                                            // reference pattern used when no regex is configured
+	PredicateType  string                                            // predicate type of the evidence
+	ProviderID     string                                            // evidence provider id passed to the attach step
+	NormalizeID    func(reference string) string                     // maps a reference found in a commit to a ticket key; nil keeps it
+	New            func(customFields []string) (IssueTracker, error) // creates the client from the environment
+}
+
+// trackerBackends are the trackers selectable with --tracker
+var trackerBackends = map[string]trackerBackend{
+	"jira": {
+		DisplayName:    "JIRA",
+		DefaultIDRegex: "[A-Z]+-[0-9]+",
+		PredicateType:  jiraPredicateType,
+		ProviderID:     "jira",
+		New: func(customFields []string) (IssueTracker, error) {
+			client, err := NewJiraClient()
+			if err != nil {
+				return nil, err
+			}
+			client.SetCustomFields(customFields)
+			return client, nil
+		},
+	},
+	"github": {
+		DisplayName:    "GitHub Issues",
+		DefaultIDRegex: githubIDRegex,
+		PredicateType:  githubPredicateType,
+		ProviderID:     "github",
+		NormalizeID:    normalizeGitHubID,
+		New: func(customFields []string) (IssueTracker, error) {
+			return NewGitHubClient()
+		},
+	},
+	"gitlab": {
+		DisplayName:    "GitLab",
+		DefaultIDRegex: gitlabIDRegex,
+		PredicateType:  gitlabPredicateType,
+		ProviderID:     "gitlab",
+		New: func(customFields []string) (IssueTracker, error) {
+			return NewGitLabClient()
+		},
+	},
+	"linear": {
+		DisplayName:    "Linear",
+		DefaultIDRegex: "[A-Z][A-Z0-9]*-[0-9]+",
+		PredicateType:  linearPredicateType,
+		ProviderID:     "linear",
+		New: func(customFields []string) (IssueTracker, error) {
+			return NewLinearClient()
+		},
+	},
+	"youtrack": {
+		DisplayName:    "YouTrack",
+		DefaultIDRegex: "[A-Z][A-Z0-9_]*-[0-9]+",
+		PredicateType:  youtrackPredicateType,
+		ProviderID:     "youtrack",
+		New: func(customFields []string) (IssueTracker, error) {
+			return NewYouTrackClient()
+		},
+	},
+}
+
+// lookupTracker returns the backend registered under name
+func lookupTracker(name string) (trackerBackend, error) {
+	backend, ok := trackerBackends[strings.ToLower(name)]
+	if !ok {
+		names := make([]string, 0, len(trackerBackends))
+		for name := range trackerBackends {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return backend, fmt.Errorf("unknown issue tracker %q, expected one of: %s", name, strings.Join(names, ", "))
+	}
+	return backend, nil
+}
+
+// normalizeIDs maps references to ticket keys, dropping duplicates while keeping the order
+func normalizeIDs(backend trackerBackend, references []string) []string {
+	if backend.NormalizeID == nil {
+		return references
+	}
+	seen := make(map[string]bool)
+	var ids []string
+	for _, reference := range references {
+		id := backend.NormalizeID(reference)
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// validateTrackerRoutes checks the project key prefixes and tracker names of the "trackers" configuration
+func validateTrackerRoutes(routes map[string]string) error {
+	for prefix, name := range routes {
+		if prefix == "" || strings.ContainsAny(prefix, "-#/") {
+			return fmt.Errorf("trackers: %q is not a project key prefix", prefix)
+		}
+		if _, err := lookupTracker(name); err != nil {
+			return fmt.Errorf("trackers: %s: %v", prefix, err)
+		}
+	}
+	return nil
+}
+
+// keyPrefix returns the project key prefix of a ticket key (ENG for ENG-123), or "" when it has none
+func keyPrefix(key string) string {
+	i := strings.LastIndex(key, "-")
+	if i <= 0 {
+		return ""
+	}
+	return strings.ToUpper(key[:i])
+}
+
+// routeTrackers returns a backend that fetches each ticket from the tracker routed for its project key prefix,
+// and tickets without a route from the fallback tracker. The regex and normalization are those of the fallback;
+// when a route points at a tracker with a different predicate type, the predicate type and provider id switch
+// to the multi-tracker values.
+func routeTrackers(fallbackName string, fallback trackerBackend, routes map[string]string) trackerBackend {
+	if len(routes) == 0 {
+		return fallback
+	}
+	routed := fallback
+	byPrefix := make(map[string]string, len(routes))
+	for prefix, name := range routes {
+		byPrefix[strings.ToUpper(prefix)] = strings.ToLower(name)
+		if trackerBackends[strings.ToLower(name)].PredicateType != fallback.PredicateType {
+			routed.PredicateType = multiTrackerPredicateType
+			routed.ProviderID = multiTrackerProviderID
+			routed.DisplayName = "the issue trackers"
+		}
+	}
+	routed.New = func(customFields []string) (IssueTracker, error) {
+		return &routedTracker{fallback: strings.ToLower(fallbackName), routes: byPrefix, customFields: customFields}, nil
+	}
+	return routed
+}
+
+// routedTracker dispatches tickets to several trackers by project key prefix.
+// Clients are created on first use, so credentials are only needed for the trackers a range references.
+type routedTracker struct {
+	fallback     string            // tracker for keys without a route
+	routes       map[string]string // upper-case project key prefix to tracker name
+	customFields []string
+}
+
+// FetchDetails implements IssueTracker, keeping the tasks in the order of ids
+func (rt *routedTracker) FetchDetails(ids []string) TransitionCheckResponse {
+	var names []string
+	idsByTracker := make(map[string][]string)
+	for _, id := range ids {
+		name, ok := rt.routes[keyPrefix(id)]
+		if !ok {
+			name = rt.fallback
+		}
+		if _, seen := idsByTracker[name]; !seen {
+			names = append(names, name)
+		}
+		idsByTracker[name] = append(idsByTracker[name], id)
+	}
+
+	tasks := make(map[string]JiraTransitionResult, len(ids))
+	for _, name := range names {
+		client, err := trackerBackends[name].New(rt.customFields)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error creating %s client for %s: %v\n", name, strings.Join(idsByTracker[name], ", "), err)
+			continue
+		}
+		// tasks come back in the order of the requested ids; a moved JIRA ticket carries its new key
+		for i, task := range client.FetchDetails(idsByTracker[name]).Tasks {
+			if i < len(idsByTracker[name]) {
+				tasks[idsByTracker[name][i]] = task
+			}
+		}
+	}
+
+	response := TransitionCheckResponse{TicketRequested: ids}
+	for _, id := range ids {
+		task, ok := tasks[id]
+		if !ok {
+			task = errorTask(id)
+		}
+		response.Tasks = append(response.Tasks, task)
+	}
+	return response
+}
+
+// FetchDetails implements IssueTracker
+func (jc *JiraClient) FetchDetails(ids []string) TransitionCheckResponse {
+	return jc.FetchJiraDetails(ids)
+}
+
+// fetchEach fetches the tickets one at a time, turning every ticket that fails into an "Error" task
+func fetchEach(ids []string, fetchIssue func(id string) (JiraTransitionResult, error)) TransitionCheckResponse {
+	response := TransitionCheckResponse{TicketRequested: ids}
+
+	for _, id := range ids {
+		task, err := fetchIssue(id)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Got error for extracting issue with id: %s error %v\n", id, err)
+			response.Tasks = append(response.Tasks, errorTask(id))
+			continue
+		}
+		response.Tasks = append(response.Tasks, task)
+	}
+
+	return response
+}
+
+// restClient sends JSON requests with token authentication to the REST APIs of the trackers and Artifactory
+type restClient struct {
+	baseURL    string
+	headers    map[string]string // authentication and API version headers sent with every request
+	httpClient *http.Client
+}
+
+// newRESTClient creates a client for baseURL sending headers with every request
+func newRESTClient(baseURL string, headers map[string]string) restClient {
+	return restClient{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		headers:    headers,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// bearerHeaders returns the headers of a JSON API authenticated with a bearer token
+func bearerHeaders(token string) map[string]string {
+	return map[string]string{"Authorization": "Bearer " + token, "Accept": "application/json"}
+}
+
+// get sends a GET request for path relative to the base URL and decodes the JSON response into v
+func (rc restClient) get(path string, v interface{}) error {
+	return rc.do(http.MethodGet, path, nil, v)
+}
+
+// do sends a request for path relative to the base URL with body encoded as JSON (unless nil),
+// and decodes the JSON response into v unless v is nil
+func (rc restClient) do(method, path string, body, v interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		jsonBytes, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %v", err)
+		}
+		reader = bytes.NewReader(jsonBytes)
+	}
+	req, err := http.NewRequest(method, rc.baseURL+path, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %v", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	for name, value := range rc.headers {
+		req.Header.Set(name, value)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := rc.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s %s failed: %v", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("%s %s failed with status %s: %s", method, path, resp.Status, strings.TrimSpace(string(message)))
+	}
+	if v == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode %s: %v", path, err)
+	}
+	return nil
+}
+
+// pageSize is the number of items requested per page from paginated APIs
+const pageSize = 100
+
+// fetchPages calls fetchPage with page numbers from 1 until a page has fewer than pageSize items
+func fetchPages[T any](fetchPage func(page int) ([]T, error)) ([]T, error) {
+	var items []T
+	for page := 1; ; page++ {
+		batch, err := fetchPage(page)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, batch...)
+		if len(batch) < pageSize {
+			return items, nil
+		}
+	}
+}
+
+// openClosedStatus maps the state of a GitHub or GitLab issue to a status name and status category
+func openClosedStatus(state string) (string, string) {
+	if state == "closed" {
+		return "Closed", "done"
+	}
+	return "Open", "new"
+}
+
+// openClosedTransition turns a GitHub or GitLab closed/reopened event into a transition; other events are ignored
+func openClosedTransition(event, author, createdAt string) (Transition, bool) {
+	var from, to string
+	switch event {
+	case "closed":
+		from, to = "Open", "Closed"
+	case "reopened":
+		from, to = "Closed", "Open"
+	default:
+		return Transition{}, false
+	}
+	return Transition{
+		FromStatus:        from,
+		ToStatus:          to,
+		Author:            author,
+		TransitionTime:    createdAt,
+		TransitionTimeUTC: formatUTC(createdAt),
+	}, true
+}
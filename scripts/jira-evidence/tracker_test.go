@@ -0,0 +1,67 @@
+package main
+
+import "testing"
+
+// fakeTracker returns a task per id, renaming the ids listed in moved as JIRA does for moved tickets
+type fakeTracker struct {
+	moved map[string]string
+}
+
+func (ft fakeTracker) FetchDetails(ids []string) TransitionCheckResponse {
+	response := TransitionCheckResponse{TicketRequested: ids}
+	for _, id := range ids {
+		key := id
+		if newKey, ok := ft.moved[id]; ok {
+			key = newKey
+		}
+		response.Tasks = append(response.Tasks, JiraTransitionResult{Key: key, Type: "Story"})
+	}
+	return response
+}
+
+func TestRouteTrackersPredicateType(t *testing.T) {
+	tests := []struct {
+		name              string
+		routes            map[string]string
+		wantPredicateType string
+		wantProviderID    string
+		wantDisplayName   string
+	}{
+		{"no routes", nil, jiraPredicateType, "jira", "JIRA"},
+		{"routes to the same tracker", map[string]string{"EV": "JIRA"}, jiraPredicateType, "jira", "JIRA"},
+		{"routes to another tracker", map[string]string{"EV": "jira", "ENG": "linear"}, multiTrackerPredicateType, multiTrackerProviderID, "the issue trackers"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			routed := routeTrackers("jira", trackerBackends["jira"], tt.routes)
+			if routed.PredicateType != tt.wantPredicateType || routed.ProviderID != tt.wantProviderID {
+				t.Errorf("routeTrackers() = %s (%s), want %s (%s)", routed.PredicateType, routed.ProviderID, tt.wantPredicateType, tt.wantProviderID)
+			}
+			if routed.DisplayName != tt.wantDisplayName {
+				t.Errorf("routeTrackers() display name = %q, want %q", routed.DisplayName, tt.wantDisplayName)
+			}
+		})
+	}
+}
+
+func TestRoutedTrackerMovedTicket(t *testing.T) {
+	trackerBackends["fake"] = trackerBackend{
+		New: func(customFields []string) (IssueTracker, error) {
+			return fakeTracker{moved: map[string]string{"OLD-1": "NEW-7"}}, nil
+		},
+	}
+	defer delete(trackerBackends, "fake")
+
+	rt := &routedTracker{fallback: "fake", routes: map[string]string{}}
+	response := rt.FetchDetails([]string{"EV-1", "OLD-1", "EV-2"})
+
+	want := []string{"EV-1", "NEW-7", "EV-2"}
+	if len(response.Tasks) != len(want) {
+		t.Fatalf("got %d tasks, want %d", len(response.Tasks), len(want))
+	}
+	for i, task := range response.Tasks {
+		if task.Key != want[i] || task.Type == "Error" {
+			t.Errorf("task %d = %s (%s), want %s", i, task.Key, task.Type, want[i])
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	jira "github.com/andygrunwald/go-jira/v2/cloud"
+)
+
+// Outcomes recorded for each post-deploy transition
+const (
+	transitionApplied = "applied" // the ticket was moved to the target status
+	transitionSkipped = "skipped" // the ticket is already in, or past, the target status
+	transitionRefused = "refused" // the workflow offers no transition to the target status from the current one
+	transitionFailed  = "failed"  // JIRA returned an error
+)
+
+// PostDeployTransition records what happened to one ticket
+type PostDeployTransition struct {
+	Key          string `json:"key"`
+	FromStatus   string `json:"from_status,omitempty"`
+	ToStatus     string `json:"to_status"`
+	TransitionID string `json:"transition_id,omitempty"`
+	Outcome      string `json:"outcome"`
+	Message      string `json:"message,omitempty"`
+}
+
+// PostDeployTransitionReport is written to the result file
+type PostDeployTransitionReport struct {
+	TargetStatus string                 `json:"target_status"`
+	Results      []PostDeployTransition `json:"results"`
+}
+
+// TransitionTo moves a ticket to the target status using whichever transition its workflow offers.
+// Tickets already in the target status or in one of pastStatuses are skipped, and so are done tickets
+// unless the target is a done status too, so a closed ticket is never moved back.
+func (jc *JiraClient) TransitionTo(key, target string, pastStatuses []string) PostDeployTransition {
+	ctx := context.Background()
+	result := PostDeployTransition{Key: key, ToStatus: target}
+
+	issue, _, err := jc.client.Issue.Get(ctx, key, &jira.GetQueryOptions{Fields: "status"})
+	if err != nil || issue == nil || issue.Fields == nil || issue.Fields.Status == nil {
+		result.Outcome = transitionFailed
+		result.Message = fmt.Sprintf("failed to get current status: %v", err)
+		return result
+	}
+	result.FromStatus = issue.Fields.Status.Name
+
+	if strings.EqualFold(result.FromStatus, target) || containsFold(pastStatuses, result.FromStatus) {
+		result.Outcome = transitionSkipped
+		result.Message = fmt.Sprintf("already in %s", result.FromStatus)
+		return result
+	}
+
+	transitions, _, err := jc.client.Issue.GetTransitions(ctx, key)
+	if err != nil {
+		result.Outcome = transitionFailed
+		result.Message = fmt.Sprintf("failed to get transitions: %v", err)
+		return result
+	}
+
+	var available []string
+	targetCategory := ""
+	for _, transition := range transitions {
+		if strings.EqualFold(transition.To.Name, target) {
+			result.TransitionID = transition.ID
+			targetCategory = transition.To.StatusCategory.Key
+			break
+		}
+		available = append(available, transition.To.Name)
+	}
+	if issue.Fields.Status.StatusCategory.Key == jira.StatusCategoryComplete && targetCategory != jira.StatusCategoryComplete {
+		result.TransitionID = ""
+		result.Outcome = transitionSkipped
+		result.Message = fmt.Sprintf("already done in %s", result.FromStatus)
+		return result
+	}
+	if result.TransitionID == "" {
+		result.Outcome = transitionRefused
+		result.Message = fmt.Sprintf("no transition from %s to %s, available: %s", result.FromStatus, target, strings.Join(available, ", "))
+		return result
+	}
+
+	resp, err := jc.client.Issue.DoTransition(ctx, key, result.TransitionID)
+	if resp != nil {
+		resp.Body.Close()
+	}
+	if err != nil {
+		result.Outcome = transitionFailed
+		result.Message = fmt.Sprintf("transition %s failed: %v", result.TransitionID, err)
+		return result
+	}
+
+	result.Outcome = transitionApplied
+	return result
+}
+
+// runTransition implements the transition subcommand: move every retrieved ticket to the target status
+func runTransition(args []string) int {
+	fs := flag.NewFlagSet("transition", flag.ExitOnError)
+	evidenceFile := fs.String("evidence", "", "Evidence JSON file written by the tool (default: $OUTPUT_FILE or transformed_jira_data.json)")
+	target := fs.String("to", "", "Target status, e.g. Released (default: $JIRA_TARGET_STATUS)")
+	pastStatuses := fs.String("past-statuses", "", "Comma-separated statuses already past the target, left untouched (default: $JIRA_PAST_STATUSES)")
+	resultsFile := fs.String("results", "", "Result file (default: $JIRA_TRANSITION_RESULTS or transition_results.json)")
+	dryRun := registerDryRunFlags(fs)
+	fs.Parse(args)
+
+	if *target == "" {
+		*target = os.Getenv("JIRA_TARGET_STATUS")
+	}
+	if *target == "" {
+		fmt.Fprintln(os.Stderr, "Error: --to is required")
+		return exitUsage
+	}
+	if *pastStatuses == "" {
+		*pastStatuses = os.Getenv("JIRA_PAST_STATUSES")
+	}
+	if *resultsFile == "" {
+		*resultsFile = os.Getenv("JIRA_TRANSITION_RESULTS")
+		if *resultsFile == "" {
+			*resultsFile = "transition_results.json"
+		}
+	}
+
+	data, err := readEvidence(evidencePath(*evidenceFile))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return exitError
+	}
+
+	jiraClient, err := dryRun.NewJiraClient()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating JIRA client: %v\n", err)
+		return exitError
+	}
+
+	report := PostDeployTransitionReport{TargetStatus: *target, Results: []PostDeployTransition{}}
+	failed := 0
+	for _, task := range data.Tasks {
+		if task.Type == "Error" {
+			continue
+		}
+		result := jiraClient.TransitionTo(task.Key, *target, parseColumns(*pastStatuses))
+		report.Results = append(report.Results, result)
+
+		switch result.Outcome {
+		case transitionApplied:
+			fmt.Printf("✅ %s: %s -> %s\n", result.Key, result.FromStatus, result.ToStatus)
+		case transitionSkipped:
+			fmt.Printf("⏭️  %s: %s\n", result.Key, result.Message)
+		case transitionRefused:
+			// The ticket did not reach the target, which the pipeline must notice
+			fmt.Fprintf(os.Stderr, "⚠️  %s: %s\n", result.Key, result.Message)
+			failed++
+		default:
+			fmt.Fprintf(os.Stderr, "❌ %s: %s\n", result.Key, result.Message)
+			failed++
+		}
+	}
+
+	jsonBytes, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error marshaling transition results: %v\n", err)
+		return exitError
+	}
+	if err := writeToFile(*resultsFile, jsonBytes); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing to file: %v\n", err)
+		return exitError
+	}
+	fmt.Printf("Transition results saved to: %s\n", *resultsFile)
+
+	if err := dryRun.finish(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return exitError
+	}
+
+	if failed > 0 {
+		fmt.Fprintf(os.Stderr, "❌ %d transition(s) failed or refused\n", failed)
+		return exitError
+	}
+	return exitSuccess
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// requiredWorkflowPolicy is the policy name recorded for the required workflow path check
+const requiredWorkflowPolicy = "required-workflow"
+
+// WorkflowRequirement lists the intermediate statuses a ticket type must pass through, in order,
+// before it is resolved
+type WorkflowRequirement struct {
+	Statuses []string `json:"statuses"`
+	Severity string   `json:"severity"` // "error" (default) or "warning"
+}
+
+// validateWorkflowRequirements checks the configured requirements
+func validateWorkflowRequirements(requirements map[string]WorkflowRequirement) error {
+	for issueType, requirement := range requirements {
+		if len(requirement.Statuses) == 0 {
+			return fmt.Errorf("required workflow for %q has no statuses", issueType)
+		}
+		switch requirement.Severity {
+		case "", severityError, severityWarning:
+		default:
+			return fmt.Errorf("required workflow for %q: unsupported severity %q, expected error or warning", issueType, requirement.Severity)
+		}
+	}
+	return nil
+}
+
+// workflowRequirementFor returns the requirement for an issue type; "*" applies to types without their own entry
+func workflowRequirementFor(requirements map[string]WorkflowRequirement, issueType string) (WorkflowRequirement, bool) {
+	for configuredType, requirement := range requirements {
+		if strings.EqualFold(configuredType, issueType) {
+			return requirement, true
+		}
+	}
+	requirement, ok := requirements["*"]
+	return requirement, ok
+}
+
+// checkRequiredWorkflow verifies that resolved tickets passed through their required statuses in order.
+// Only the path since the ticket was last reopened counts, so a reopened ticket must go through the steps again.
+func checkRequiredWorkflow(tasks []JiraTransitionResult, requirements map[string]WorkflowRequirement) []PolicyResult {
+	done := doneStatuses()
+
+	var results []PolicyResult
+	for _, task := range tasks {
+		if task.Type == "Error" {
+			continue
+		}
+		requirement, ok := workflowRequirementFor(requirements, task.Type)
+		if !ok {
+			continue
+		}
+
+		severity := requirement.Severity
+		if severity == "" {
+			severity = severityError
+		}
+		result := PolicyResult{Policy: requiredWorkflowPolicy, Key: task.Key, Severity: severity}
+
+		// Collect the statuses visited on the way to the final resolution
+		var path []string
+		resolvedInto := ""
+		for _, transition := range sortTransitions(task.Transitions) {
+			fromDone := done[strings.ToLower(transition.FromStatus)]
+			toDone := done[strings.ToLower(transition.ToStatus)]
+
+			if fromDone && !toDone {
+				// Reopened: the required steps have to be repeated
+				path = nil
+				resolvedInto = ""
+			}
+			if len(path) == 0 && !fromDone {
+				path = append(path, transition.FromStatus)
+			}
+			if toDone {
+				if resolvedInto == "" {
+					resolvedInto = transition.ToStatus
+				}
+				continue
+			}
+			path = append(path, transition.ToStatus)
+		}
+
+		if resolvedInto == "" {
+			result.Passed = true
+			result.Message = fmt.Sprintf("not resolved yet (status %q)", task.Status)
+			results = append(results, result)
+			continue
+		}
+
+		// Required statuses must appear in the visited path as an ordered subsequence
+		var missing []string
+		next := 0
+		for _, required := range requirement.Statuses {
+			start := next
+			found := false
+			for next < len(path) {
+				visited := path[next]
+				next++
+				if strings.EqualFold(visited, required) {
+					found = true
+					break
+				}
+			}
+			if !found {
+				missing = append(missing, required)
+				next = start
+			}
+		}
+
+		switch {
+		case len(missing) == 0:
+			result.Passed = true
+			result.Message = fmt.Sprintf("passed through %s before %s", strings.Join(requirement.Statuses, " → "), resolvedInto)
+		case len(path) <= 1:
+			result.Message = fmt.Sprintf("moved straight to %s from %s, skipping %s",
+				resolvedInto, strings.Join(path, ""), strings.Join(requirement.Statuses, " → "))
+		default:
+			result.Message = fmt.Sprintf("skipped required step(s) %s before %s (path: %s)",
+				strings.Join(missing, ", "), resolvedInto, strings.Join(path, " → "))
+		}
+
+		results = append(results, result)
+	}
+
+	return results
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestCheckRequiredWorkflow(t *testing.T) {
+	requirements := map[string]WorkflowRequirement{
+		"story": {Statuses: []string{"In Review", "QA"}},
+		"*":     {Statuses: []string{"In Review"}, Severity: severityWarning},
+	}
+	at := func(hour int) string {
+		return fmt.Sprintf("2024-01-02T%02d:00:00.000+0000", hour)
+	}
+
+	tests := []struct {
+		name        string
+		transitions []Transition
+		wantPassed  bool
+		wantInMsg   string
+	}{
+		{
+			name: "all steps in order",
+			transitions: []Transition{
+				statusChange("To Do", "In Progress", "Jane Doe", at(1)),
+				statusChange("In Progress", "In Review", "Jane Doe", at(2)),
+				statusChange("In Review", "QA", "John Roe", at(3)),
+				statusChange("QA", "Done", "John Roe", at(4)),
+			},
+			wantPassed: true,
+			wantInMsg:  "passed through In Review → QA before Done",
+		},
+		{
+			name: "step skipped",
+			transitions: []Transition{
+				statusChange("To Do", "In Progress", "Jane Doe", at(1)),
+				statusChange("In Progress", "QA", "Jane Doe", at(2)),
+				statusChange("QA", "Done", "John Roe", at(3)),
+			},
+			wantInMsg: "skipped required step(s) In Review before Done (path: To Do → In Progress → QA)",
+		},
+		{
+			name: "steps in the wrong order",
+			transitions: []Transition{
+				statusChange("To Do", "QA", "Jane Doe", at(1)),
+				statusChange("QA", "In Review", "Jane Doe", at(2)),
+				statusChange("In Review", "Done", "John Roe", at(3)),
+			},
+			wantInMsg: "skipped required step(s) QA",
+		},
+		{
+			name: "moved straight to done",
+			transitions: []Transition{
+				statusChange("To Do", "Done", "Jane Doe", at(1)),
+			},
+			wantInMsg: "moved straight to Done from To Do",
+		},
+		{
+			name: "reopened and resolved without repeating the steps",
+			transitions: []Transition{
+				statusChange("To Do", "In Review", "Jane Doe", at(1)),
+				statusChange("In Review", "QA", "John Roe", at(2)),
+				statusChange("QA", "Done", "John Roe", at(3)),
+				statusChange("Done", "In Progress", "Jane Doe", at(4)),
+				statusChange("In Progress", "Done", "Jane Doe", at(5)),
+			},
+			wantInMsg: "moved straight to Done from In Progress",
+		},
+		{
+			name: "transitions listed out of order",
+			transitions: []Transition{
+				statusChange("QA", "Done", "John Roe", at(4)),
+				statusChange("In Review", "QA", "John Roe", at(3)),
+				statusChange("To Do", "In Review", "Jane Doe", at(2)),
+			},
+			wantPassed: true,
+		},
+		{
+			name: "not resolved yet",
+			transitions: []Transition{
+				statusChange("To Do", "In Progress", "Jane Doe", at(1)),
+			},
+			wantPassed: true,
+			wantInMsg:  "not resolved yet",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := checkRequiredWorkflow([]JiraTransitionResult{storyTicket("EV-1", "", tt.transitions...)}, requirements)
+			if len(results) != 1 {
+				t.Fatalf("got %d results, want 1", len(results))
+			}
+			result := results[0]
+			if result.Passed != tt.wantPassed || result.Policy != requiredWorkflowPolicy || result.Severity != severityError {
+				t.Errorf("result = %+v, want passed %v with error severity", result, tt.wantPassed)
+			}
+			if !strings.Contains(result.Message, tt.wantInMsg) {
+				t.Errorf("message %q does not contain %q", result.Message, tt.wantInMsg)
+			}
+		})
+	}
+}
+
+func TestCheckRequiredWorkflowIssueTypes(t *testing.T) {
+	straightToDone := statusChange("To Do", "Done", "Jane Doe", "2024-01-02T10:00:00.000+0000")
+	bug := storyTicket("EV-2", "Done", straightToDone)
+	bug.Type = "Bug"
+	unretrieved := JiraTransitionResult{Key: "EV-3", Type: "Error"}
+
+	results := checkRequiredWorkflow([]JiraTransitionResult{bug, unretrieved}, map[string]WorkflowRequirement{
+		"Story": {Statuses: []string{"QA"}},
+		"*":     {Statuses: []string{"In Review"}, Severity: severityWarning},
+	})
+	if len(results) != 1 || results[0].Key != "EV-2" || results[0].Passed || results[0].Severity != severityWarning {
+		t.Errorf("results = %+v, want a failed warning for the bug from the * requirement", results)
+	}
+
+	results = checkRequiredWorkflow([]JiraTransitionResult{bug}, map[string]WorkflowRequirement{"Story": {Statuses: []string{"QA"}}})
+	if len(results) != 0 {
+		t.Errorf("results = %+v, want none for a type without a requirement", results)
+	}
+}
+
+func TestValidateWorkflowRequirements(t *testing.T) {
+	tests := []struct {
+		name         string
+		requirements map[string]WorkflowRequirement
+		wantErr      bool
+	}{
+		{"valid", map[string]WorkflowRequirement{"Story": {Statuses: []string{"QA"}, Severity: "warning"}}, false},
+		{"no statuses", map[string]WorkflowRequirement{"Story": {}}, true},
+		{"unknown severity", map[string]WorkflowRequirement{"Story": {Statuses: []string{"QA"}, Severity: "fatal"}}, true},
+	}
+	for _, tt := range tests {
+		if err := validateWorkflowRequirements(tt.requirements); (err != nil) != tt.wantErr {
+			t.Errorf("%s: validateWorkflowRequirements() error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
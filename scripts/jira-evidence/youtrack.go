@@ -0,0 +1,202 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// youtrackIssueFields are the issue fields requested from the YouTrack REST API
+const youtrackIssueFields = "idReadable,summary,description,created,updated,resolved," +
+	"project(shortName),reporter(login,fullName),tags(name),customFields(name,value(name,login,fullName,isResolved))"
+
+// youtrackActivityFields are the activity fields requested for state changes
+const youtrackActivityFields = "timestamp,author(login,fullName),field(name),added(name),removed(name)"
+
+// YouTrackClient reads issues from the YouTrack REST API
+type YouTrackClient struct {
+	restClient
+}
+
+// youtrackUser is the subset of a YouTrack user used here
+type youtrackUser struct {
+	Login    string `json:"login"`
+	FullName string `json:"fullName"`
+}
+
+// youtrackValue is the subset of a custom field value used here; enum, state and user values all decode into it
+type youtrackValue struct {
+	Name       string `json:"name"`
+	Login      string `json:"login"`
+	FullName   string `json:"fullName"`
+	IsResolved bool   `json:"isResolved"`
+}
+
+// youtrackIssue is the subset of a YouTrack issue used here
+type youtrackIssue struct {
+	IDReadable  string       `json:"idReadable"`
+	Summary     string       `json:"summary"`
+	Description string       `json:"description"`
+	Created     int64        `json:"created"`
+	Updated     int64        `json:"updated"`
+	Resolved    int64        `json:"resolved"`
+	Reporter    youtrackUser `json:"reporter"`
+	Project     struct {
+		ShortName string `json:"shortName"`
+	} `json:"project"`
+	Tags []struct {
+		Name string `json:"name"`
+	} `json:"tags"`
+	CustomFields []struct {
+		Name  string          `json:"name"`
+		Value json.RawMessage `json:"value"`
+	} `json:"customFields"`
+}
+
+// youtrackActivity is the subset of a YouTrack custom field activity used here
+type youtrackActivity struct {
+	Timestamp int64        `json:"timestamp"`
+	Author    youtrackUser `json:"author"`
+	Field     struct {
+		Name string `json:"name"`
+	} `json:"field"`
+	Added   json.RawMessage `json:"added"`
+	Removed json.RawMessage `json:"removed"`
+}
+
+// NewYouTrackClient creates a YouTrack client from YOUTRACK_URL and YOUTRACK_TOKEN
+func NewYouTrackClient() (*YouTrackClient, error) {
+	baseURL := os.Getenv("YOUTRACK_URL")
+	if baseURL == "" {
+		return nil, fmt.Errorf("YouTrack URL not found, set YOUTRACK_URL variable")
+	}
+	token := os.Getenv("YOUTRACK_TOKEN")
+	if token == "" {
+		return nil, fmt.Errorf("YouTrack token not found, set YOUTRACK_TOKEN variable")
+	}
+
+	return &YouTrackClient{restClient: newRESTClient(baseURL, bearerHeaders(token))}, nil
+}
+
+// stateActivities returns every custom field activity of an issue, following pagination
+func (yc *YouTrackClient) stateActivities(id string) ([]youtrackActivity, error) {
+	return fetchPages(func(page int) ([]youtrackActivity, error) {
+		var batch []youtrackActivity
+		path := fmt.Sprintf("/api/issues/%s/activities?categories=CustomFieldCategory&fields=%s&$top=%d&$skip=%d",
+			url.PathEscape(id), url.QueryEscape(youtrackActivityFields), pageSize, (page-1)*pageSize)
+		return batch, yc.get(path, &batch)
+	})
+}
+
+// youtrackValues decodes a custom field value or activity change, which is null, one object or a list
+func youtrackValues(raw json.RawMessage) []youtrackValue {
+	var values []youtrackValue
+	if err := json.Unmarshal(raw, &values); err == nil {
+		return values
+	}
+	var value *youtrackValue
+	if err := json.Unmarshal(raw, &value); err == nil && value != nil {
+		return []youtrackValue{*value}
+	}
+	return nil
+}
+
+// youtrackTime formats a YouTrack timestamp (milliseconds since the epoch); zero means unset
+func youtrackTime(millis int64) string {
+	if millis == 0 {
+		return ""
+	}
+	return time.UnixMilli(millis).UTC().Format("2006-01-02T15:04:05.000Z")
+}
+
+// FetchDetails implements IssueTracker for YouTrack. Keys are readable issue IDs such as PRJ-123;
+// changes of the State field become transitions.
+func (yc *YouTrackClient) FetchDetails(ids []string) TransitionCheckResponse {
+	return fetchEach(ids, yc.fetchIssue)
+}
+
+// fetchIssue retrieves one issue and its State changes
+func (yc *YouTrackClient) fetchIssue(id string) (JiraTransitionResult, error) {
+	var issue youtrackIssue
+	path := fmt.Sprintf("/api/issues/%s?fields=%s", url.PathEscape(id), url.QueryEscape(youtrackIssueFields))
+	if err := yc.get(path, &issue); err != nil {
+		return JiraTransitionResult{}, err
+	}
+	activities, err := yc.stateActivities(id)
+	if err != nil {
+		return JiraTransitionResult{}, err
+	}
+
+	task := JiraTransitionResult{
+		Key:         id,
+		Summary:     issue.Summary,
+		Description: issue.Description,
+		Project:     issue.Project.ShortName,
+		Created:     youtrackTime(issue.Created),
+		Updated:     youtrackTime(issue.Updated),
+		Resolved:    youtrackTime(issue.Resolved),
+		Reporter:    issue.Reporter.FullName,
+		Transitions: []Transition{},
+	}
+	for _, tag := range issue.Tags {
+		task.Labels = append(task.Labels, tag.Name)
+	}
+
+	// State, Type, Priority and Assignee are custom fields in YouTrack
+	for _, field := range issue.CustomFields {
+		values := youtrackValues(field.Value)
+		if len(values) == 0 {
+			continue
+		}
+		switch field.Name {
+		case "State":
+			task.Status = values[0].Name
+			task.StatusCategory = "indeterminate"
+			if values[0].IsResolved {
+				task.StatusCategory = "done"
+			}
+		case "Type":
+			task.Type = values[0].Name
+		case "Priority":
+			task.Priority = values[0].Name
+		case "Assignee":
+			var assignees []string
+			for _, value := range values {
+				assignees = append(assignees, value.FullName)
+			}
+			joined := strings.Join(assignees, ", ")
+			task.Assignee = &joined
+		}
+	}
+
+	for _, activity := range activities {
+		if activity.Field.Name != "State" {
+			continue
+		}
+		var from, to string
+		if removed := youtrackValues(activity.Removed); len(removed) > 0 {
+			from = removed[0].Name
+		}
+		if added := youtrackValues(activity.Added); len(added) > 0 {
+			to = added[0].Name
+		}
+		if to == "" {
+			continue
+		}
+		transitionTime := youtrackTime(activity.Timestamp)
+		task.Transitions = append(task.Transitions, Transition{
+			FromStatus:        from,
+			ToStatus:          to,
+			Author:            activity.Author.FullName,
+			TransitionTime:    transitionTime,
+			TransitionTimeUTC: transitionTime,
+		})
+	}
+	task.Transitions = sortTransitions(task.Transitions)
+	task.Timeline = buildTimeline(task)
+
+	return task, nil
+}